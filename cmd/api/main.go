@@ -52,23 +52,30 @@ func main() {
 		metrics = observability.NewMetrics(obsConfig.ServiceName)
 	}
 
+	// Card data is embedded, unless DATA_DIR points at a directory to load
+	// (and reload) it from
 	logger.Info("Loading card data...")
-	store, err := data.NewStore(metrics)
+	provider, err := data.NewProvider(data.LoadProviderConfig(), metrics)
 	if err != nil {
 		logger.Error("Failed to load data", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
 
-	dataStats, indexStats := store.Stats()
-	observability.LogDataLoaded(logger, dataStats)
-
-	// Set data metrics
-	if metrics != nil {
-		metrics.SetDataStats(dataStats)
-		metrics.SetIndexStats(indexStats)
+	if issues := provider.Store().Validate(); len(issues) > 0 {
+		logger.Warn("Card data has validation issues, run goagain validate for details",
+			slog.Int("issues", len(issues)),
+			slog.String("first", issues[0].String()),
+		)
 	}
 
-	router := api.NewRouter(store, logger, metrics, obsConfig)
+	// Reload data on SIGHUP
+	server.ReloadOnSignal(ctx, provider, logger)
+
+	// The provider sets the data metrics for each store it serves
+	dataStats, _ := provider.Store().Stats()
+	observability.LogDataLoaded(logger, dataStats)
+
+	router := api.NewRouter(provider, logger, metrics, obsConfig)
 
 	// Wrap with OTel HTTP tracing
 	handler := otelhttp.NewHandler(router, "goagain-api",
@@ -0,0 +1,162 @@
+// Package main provides the goagain command-line tool for working with
+// Flesh and Blood card data.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/oleiade/goagain/internal/data"
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+const usage = `Usage: goagain <command> [flags]
+
+Commands:
+  validate    Check card data for broken references, duplicate IDs and unknown codes
+  diff        List the cards added, removed or changed between two data snapshots
+
+Run "goagain <command> -h" for a command's flags.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "validate":
+		os.Exit(runValidate(os.Args[2:]))
+	case "diff":
+		os.Exit(runDiff(os.Args[2:]))
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "goagain: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+// loadStore loads the store from dir, or from the embedded data when dir is empty.
+func loadStore(dir string) (*data.Store, error) {
+	if dir == "" {
+		return data.NewStore(nil)
+	}
+	return data.NewStoreFromDir(dir, nil)
+}
+
+// runValidate validates card data and reports every issue found. It returns
+// the process exit code: 1 when issues were found or the data did not load.
+func runValidate(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	dir := flags.String("dir", os.Getenv("DATA_DIR"), "Directory to validate (default: DATA_DIR, or the embedded data)")
+	asJSON := flags.Bool("json", false, "Print issues as JSON")
+	_ = flags.Parse(args)
+
+	store, err := loadStore(*dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goagain validate: %v\n", err)
+		return 1
+	}
+
+	issues := store.Validate()
+
+	if *asJSON {
+		if issues == nil {
+			issues = make([]data.ValidationIssue, 0)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(map[string]any{
+			"data_version": store.Version,
+			"issues":       issues,
+		})
+	} else {
+		for _, issue := range issues {
+			fmt.Printf("%s [%s]\n", issue, issue.Code)
+		}
+		fmt.Printf("%d issues found in data version %s\n", len(issues), store.Version)
+	}
+
+	if len(issues) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runDiff compares two data snapshots and reports the changed cards. It
+// returns the process exit code.
+func runDiff(args []string) int {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	fromDir := flags.String("from", "", "Directory of the old snapshot (default: the embedded data)")
+	toDir := flags.String("to", os.Getenv("DATA_DIR"), "Directory of the new snapshot (default: DATA_DIR, or the embedded data)")
+	asJSON := flags.Bool("json", false, "Print the changelog as JSON")
+	_ = flags.Parse(args)
+
+	from, err := loadStore(*fromDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goagain diff: loading -from: %v\n", err)
+		return 1
+	}
+
+	to, err := loadStore(*toDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goagain diff: loading -to: %v\n", err)
+		return 1
+	}
+
+	changelog := data.Diff(from, to)
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(changelog)
+		return 0
+	}
+
+	printChangelog(os.Stdout, changelog)
+	return 0
+}
+
+// printChangelog writes a human-readable changelog report.
+func printChangelog(w io.Writer, changelog *domain.Changelog) {
+	summary := changelog.Summary
+	fmt.Fprintf(w, "Data changelog %s -> %s\n", changelog.FromVersion, changelog.ToVersion)
+	fmt.Fprintf(w, "%d added, %d removed, %d changed (%d text, %d stats, %d legality changes)\n",
+		summary.Added, summary.Removed, summary.Changed, summary.Text, summary.Stats, summary.Legality)
+
+	if len(changelog.Added) > 0 {
+		fmt.Fprintf(w, "\nAdded:\n")
+		for _, card := range changelog.Added {
+			fmt.Fprintf(w, "  + %s\n", cardLabel(card))
+		}
+	}
+
+	if len(changelog.Removed) > 0 {
+		fmt.Fprintf(w, "\nRemoved:\n")
+		for _, card := range changelog.Removed {
+			fmt.Fprintf(w, "  - %s\n", cardLabel(card))
+		}
+	}
+
+	if len(changelog.Changed) > 0 {
+		fmt.Fprintf(w, "\nChanged:\n")
+		for _, change := range changelog.Changed {
+			fmt.Fprintf(w, "  ~ %s\n", cardLabel(change.CardSummary))
+			for _, field := range change.Changes {
+				fmt.Fprintf(w, "      %s: %q -> %q\n", field.Field, field.Before, field.After)
+			}
+		}
+	}
+}
+
+func cardLabel(card domain.CardSummary) string {
+	if card.Pitch != "" {
+		return fmt.Sprintf("%s (pitch %s) [%s]", card.Name, card.Pitch, card.UniqueID)
+	}
+	return fmt.Sprintf("%s [%s]", card.Name, card.UniqueID)
+}
@@ -59,29 +59,36 @@ func main() {
 		metrics = observability.NewMetrics(obsConfig.ServiceName)
 	}
 
+	// Card data is embedded, unless DATA_DIR points at a directory to load
+	// (and reload) it from
 	logger.Info("Loading card data...")
-	store, err := data.NewStore(metrics)
+	provider, err := data.NewProvider(data.LoadProviderConfig(), metrics)
 	if err != nil {
 		logger.Error("Failed to load data", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
 
-	dataStats, indexStats := store.Stats()
-	observability.LogDataLoaded(logger, dataStats)
-
-	// Set data metrics
-	if metrics != nil {
-		metrics.SetDataStats(dataStats)
-		metrics.SetIndexStats(indexStats)
+	if issues := provider.Store().Validate(); len(issues) > 0 {
+		logger.Warn("Card data has validation issues, run goagain validate for details",
+			slog.Int("issues", len(issues)),
+			slog.String("first", issues[0].String()),
+		)
 	}
 
-	mcpServer := fabmcp.NewServer(store, logger, metrics)
+	// Reload data on SIGHUP
+	server.ReloadOnSignal(ctx, provider, logger)
+
+	// The provider sets the data metrics for each store it serves
+	dataStats, _ := provider.Store().Stats()
+	observability.LogDataLoaded(logger, dataStats)
+
+	mcpServer := fabmcp.NewServer(provider, logger, metrics)
 
 	switch *mode {
 	case "stdio":
 		runStdio(mcpServer, logger)
 	case "http":
-		runHTTP(mcpServer, *port, logger, metrics)
+		runHTTP(mcpServer, provider, *port, logger, metrics)
 	default:
 		logger.Error("Unknown mode", slog.String("mode", *mode))
 		os.Exit(1)
@@ -96,7 +103,7 @@ func runStdio(mcpServer *fabmcp.Server, logger *slog.Logger) {
 	}
 }
 
-func runHTTP(mcpServer *fabmcp.Server, port int, logger *slog.Logger, metrics *observability.Metrics) {
+func runHTTP(mcpServer *fabmcp.Server, provider *data.Provider, port int, logger *slog.Logger, metrics *observability.Metrics) {
 	httpServer := mcp.NewStreamableHTTPServer(mcpServer.MCPServer())
 
 	// Create a mux to add health endpoint
@@ -110,6 +117,11 @@ func runHTTP(mcpServer *fabmcp.Server, port int, logger *slog.Logger, metrics *o
 		})
 	})
 
+	// Data reload endpoint, only served when a token is configured
+	if token := os.Getenv("ADMIN_TOKEN"); token != "" {
+		mux.HandleFunc("POST /admin/reload", server.ReloadHandler(provider, token, logger))
+	}
+
 	// MCP endpoint (handles /mcp by default)
 	mux.Handle("/", httpServer)
 
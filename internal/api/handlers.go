@@ -3,35 +3,72 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/oleiade/goagain/internal/data"
 	"github.com/oleiade/goagain/internal/domain"
+	"github.com/oleiade/goagain/internal/observability"
+	"github.com/oleiade/goagain/internal/query"
+	"github.com/oleiade/goagain/internal/render"
 )
 
 // Handler holds the dependencies for HTTP handlers.
 type Handler struct {
-	store      *data.Store
+	provider   *data.Provider
 	apiBaseURL string
 	mcpBaseURL string
+
+	// store is the store a request is served from, set by bind
+	store *data.Store
 }
 
-// NewHandler creates a new Handler with the given data store.
-func NewHandler(store *data.Store, apiBaseURL, mcpBaseURL string) *Handler {
+// NewHandler creates a new Handler serving the provider's data.
+func NewHandler(provider *data.Provider, apiBaseURL, mcpBaseURL string) *Handler {
 	return &Handler{
-		store:      store,
+		provider:   provider,
 		apiBaseURL: apiBaseURL,
 		mcpBaseURL: mcpBaseURL,
 	}
 }
 
+// bind adapts a handler method into a handler func that serves each request
+// from the store current when the request started, so a reload never changes
+// the data under a request.
+func (h *Handler) bind(handle func(*Handler, http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bound := *h
+		bound.store = h.provider.Store()
+		handle(&bound, w, r)
+	}
+}
+
 // Response types
 
 // ErrorResponse represents an API error response.
 type ErrorResponse struct {
 	Error string `json:"error"`
+
+	// Suggestions are close matches for a name that was not found
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// QueryErrorResponse reports a query language syntax error and where it is.
+type QueryErrorResponse struct {
+	Error    string `json:"error"`
+	Position int    `json:"position"` // 1-based offset of the offending token
+	Token    string `json:"token,omitempty"`
+}
+
+// MultipleChoicesResponse lists the cards an ambiguous card name matches.
+type MultipleChoicesResponse struct {
+	Error      string               `json:"error"`
+	Candidates []domain.CardSummary `json:"candidates"`
 }
 
 // PaginatedResponse wraps paginated results.
@@ -40,12 +77,20 @@ type PaginatedResponse struct {
 	Total  int `json:"total"`
 	Limit  int `json:"limit"`
 	Offset int `json:"offset"`
+
+	// Facets counts every match, not just this page, by the requested facets
+	Facets data.FacetCounts `json:"facets,omitempty"`
+
+	// Cursors to the neighbouring pages, also sent in the Link header
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 // HealthResponse represents the health check response.
 type HealthResponse struct {
-	Status string         `json:"status"`
-	Stats  map[string]int `json:"stats"`
+	Status      string         `json:"status"`
+	DataVersion string         `json:"data_version"`
+	Stats       map[string]int `json:"stats"`
 }
 
 // Helper functions
@@ -60,6 +105,36 @@ func writeError(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, ErrorResponse{Error: message})
 }
 
+// writeCursorError reports a cursor that cannot be resumed from: 410 Gone
+// when the data changed since it was issued, 400 otherwise.
+func writeCursorError(w http.ResponseWriter, err error) {
+	var cursorErr *data.CursorError
+	if errors.As(err, &cursorErr) && cursorErr.Stale {
+		writeError(w, http.StatusGone, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+// setPageLinks sets the Link header to the next and previous pages, which
+// repeat the request with a cursor in place of the offset.
+func setPageLinks(w http.ResponseWriter, r *http.Request, next, prev string) {
+	var links []string
+	for _, link := range []struct{ rel, cursor string }{{"next", next}, {"prev", prev}} {
+		if link.cursor == "" {
+			continue
+		}
+		query := r.URL.Query()
+		query.Del("offset")
+		query.Set("cursor", link.cursor)
+		target := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf("<%s>; rel=%q", target.String(), link.rel))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
 func getIntParam(r *http.Request, name string, defaultVal int) int {
 	val := r.URL.Query().Get(name)
 	if val == "" {
@@ -72,8 +147,105 @@ func getIntParam(r *http.Request, name string, defaultVal int) int {
 	return intVal
 }
 
+// getDateParam parses an optional date query parameter. It returns the zero
+// time when the parameter is absent.
+func getDateParam(r *http.Request, name string) (time.Time, error) {
+	val := r.URL.Query().Get(name)
+	if val == "" {
+		return time.Time{}, nil
+	}
+	return domain.ParseDate(val)
+}
+
+// getStatRanges applies the stat range parameters, such as cost_min or
+// power_gte, to the filter. It returns the name of the first parameter that is
+// not a whole number.
+func getStatRanges(r *http.Request, filter *data.CardFilter) (string, bool) {
+	query := r.URL.Query()
+	for _, stat := range domain.StatNames {
+		for _, suffix := range data.StatBoundSuffixes {
+			name := string(stat) + "_" + suffix
+			val := query.Get(name)
+			if val == "" {
+				continue
+			}
+			value, err := strconv.Atoi(val)
+			if err != nil {
+				return name, false
+			}
+			filter.SetStatBound(stat, suffix, value)
+		}
+	}
+	filter.IncludeVariable = query.Get("include_variable") == "true"
+	return "", true
+}
+
+// getValueFilters parses the class, type, keyword, set and pitch parameters.
+// Each takes comma-separated values, matched per its <name>_mode parameter:
+// any (the default) or all. Prefixed with a dash, as in -type=Equipment, the
+// parameter excludes the cards having any of the values.
+func getValueFilters(r *http.Request, filter *data.CardFilter) error {
+	query := r.URL.Query()
+	for _, attribute := range data.CardAttributes {
+		name := string(attribute)
+		mode, err := data.ParseMatchMode(query.Get(name + "_mode"))
+		if err != nil {
+			return fmt.Errorf("invalid %s_mode %q, expected any or all", name, query.Get(name+"_mode"))
+		}
+
+		if values := data.SplitValues(query.Get(name)); len(values) > 0 {
+			filter.Values = append(filter.Values, data.ValueFilter{
+				Attribute: attribute,
+				Values:    values,
+				Mode:      mode,
+			})
+		}
+		if values := data.SplitValues(query.Get("-" + name)); len(values) > 0 {
+			filter.Values = append(filter.Values, data.ValueFilter{
+				Attribute: attribute,
+				Values:    values,
+				Negate:    true,
+			})
+		}
+	}
+	return nil
+}
+
+// getCardSort parses the sort and order parameters of card searches.
+func getCardSort(r *http.Request, filter *data.CardFilter) error {
+	var err error
+	if filter.Sort, err = data.ParseCardSort(r.URL.Query().Get("sort")); err != nil {
+		return err
+	}
+	filter.Order, err = data.ParseCardOrder(filter.Sort, r.URL.Query().Get("order"))
+	return err
+}
+
 // Handlers
 
+// invalidRenderMessage is the error returned for an unknown render parameter.
+const invalidRenderMessage = "invalid render mode, expected plain, html, markdown or raw"
+
+// getRenderParam parses the render query parameter, defaulting to the raw text.
+func getRenderParam(r *http.Request) (render.Mode, bool) {
+	mode, err := render.ParseMode(r.URL.Query().Get("render"))
+	return mode, err == nil
+}
+
+// renderCards returns the cards with their functional text rendered in the
+// given mode, leaving the stored cards untouched.
+func (h *Handler) renderCards(cards []*domain.Card, mode render.Mode) []*domain.Card {
+	if mode == render.ModeRaw {
+		return cards
+	}
+
+	rendered := make([]*domain.Card, len(cards))
+	for i, card := range cards {
+		rendered[i] = h.store.RenderCard(card, mode)
+	}
+	return rendered
+}
+
 // Index serves the landing page (HTML) or API info (JSON).
 // Returns JSON if Accept header contains "application/json".
 func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
@@ -92,18 +264,37 @@ func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
 			"version":     "1.0.0",
 			"api_version": "v1",
 			"endpoints": map[string]string{
-				"GET /":                       "Landing page (HTML) or API info (JSON with Accept: application/json)",
-				"GET /health":                 "Health check with stats",
-				"GET /docs":                   "Interactive API documentation (Swagger UI)",
-				"GET /openapi.yaml":           "OpenAPI 3.0 specification",
-				"GET /v1/cards":               "List/search cards (params: name, type, class, set, pitch, keyword, q, legal_in, limit, offset)",
-				"GET /v1/cards/{id}":          "Get card by unique_id or name",
-				"GET /v1/cards/{id}/legality": "Get card legality across all formats",
-				"GET /v1/sets":                "List/search sets (params: name, id, q)",
-				"GET /v1/sets/{id}":           "Get set details with cards",
-				"GET /v1/keywords":            "List all keywords",
-				"GET /v1/keywords/{name}":     "Get keyword description",
-				"GET /v1/abilities":           "List all abilities",
+				"GET /":                            "Landing page (HTML) or API info (JSON with Accept: application/json)",
+				"GET /health":                      "Health check with stats",
+				"GET /docs":                        "Interactive API documentation (Swagger UI)",
+				"GET /openapi.yaml":                "OpenAPI 3.0 specification",
+				"GET /v1/cards":                    "List/search cards (params: name, type, class, set, pitch, keyword as comma-separated lists, <param>_mode=any|all, -<param> to exclude, q, legal_in, as_of, <stat>_min/_max/_gte/_lte/_gt/_lt, include_variable, sort, order, facets, collapse_faces, render, limit, offset, cursor)",
+				"GET /v1/cards/search":             "Search cards with the query language, e.g. c:ninja pow>=4 -legal:blitz (params: query, sort, order, collapse_faces, render, limit, offset, cursor)",
+				"GET /v1/cards/autocomplete":       "Complete card names from a prefix (params: prefix, limit)",
+				"GET /v1/cards/{id}":               "Get card by unique_id, printing ID or name, forgiving typos (params: pitch, color, render)",
+				"GET /v1/cards/{id}/legality":      "Get card legality across all formats (params: pitch, color, as_of)",
+				"GET /v1/cards/{id}/references":    "List cards referenced by a card, such as the tokens it creates",
+				"GET /v1/cards/{id}/referenced-by": "List cards that reference a card",
+				"GET /v1/cards/{id}/graph":         "Walk the card reference graph (params: depth, direction)",
+				"GET /v1/cards/{id}/faces":         "Get every face of a multi-faced card",
+				"GET /v1/printings":                "List/search printings (params: set, rarity, foiling, edition, art_variation, names, render, limit, offset)",
+				"GET /v1/printings/{id}":           "Get printings by unique_id or collector ID (e.g., WTR001), with their cards (params: names, render)",
+				"GET /v1/artists":                  "List/search artists with printing counts per set (params: q)",
+				"GET /v1/artists/{name}":           "Get an artist with printing counts per set",
+				"GET /v1/artists/{name}/printings": "List the printings an artist is credited on (params: set, names, render, limit, offset)",
+				"GET /v1/sets":                     "List/search sets (params: name, id, q, sort, order, limit, cursor)",
+				"GET /v1/sets/{id}":                "Get set details with cards (params: render)",
+				"GET /v1/keywords":                 "List all keywords",
+				"GET /v1/keywords/{name}":          "Get keyword description",
+				"GET /v1/abilities":                "List all abilities",
+				"GET /v1/rarities":                 "List rarity codes",
+				"GET /v1/foilings":                 "List foiling codes",
+				"GET /v1/editions":                 "List edition codes",
+				"GET /v1/art-variations":           "List art variation codes",
+				"GET /v1/formats":                  "List formats with their banned, suspended, living legend and restricted cards (params: as_of)",
+				"GET /v1/formats/{format}":         "Get a format with its banned, suspended, living legend and restricted cards (params: as_of)",
+				"GET /v1/legality/changes":         "List legality changes announced or taking effect in a date window (params: since, until, format)",
+				"GET /v1/changelog":                "List cards added, removed or changed by the last data reload",
 			},
 			"stats": dataStats,
 		}
@@ -123,8 +314,9 @@ func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	dataStats, _ := h.store.Stats()
 	writeJSON(w, http.StatusOK, HealthResponse{
-		Status: "ok",
-		Stats:  dataStats,
+		Status:      "ok",
+		DataVersion: h.store.Version,
+		Stats:       dataStats,
 	})
 }
 
@@ -134,14 +326,13 @@ func (h *Handler) ListCards(w http.ResponseWriter, r *http.Request) {
 
 	filter := data.CardFilter{
 		Name:      query.Get("name"),
-		Type:      query.Get("type"),
-		Class:     query.Get("class"),
-		SetID:     query.Get("set"),
-		Pitch:     query.Get("pitch"),
-		Keyword:   query.Get("keyword"),
 		TextQuery: query.Get("q"),
 		Limit:     getIntParam(r, "limit", 50),
 		Offset:    getIntParam(r, "offset", 0),
+		Cursor:    query.Get("cursor"),
+
+		// Multi-faced cards are listed once unless the client opts out
+		CollapseFaces: query.Get("collapse_faces") != "false",
 	}
 
 	// Parse format legality filter
@@ -149,25 +340,151 @@ func (h *Handler) ListCards(w http.ResponseWriter, r *http.Request) {
 		filter.LegalIn = domain.Format(legalIn)
 	}
 
+	asOf, err := getDateParam(r, "as_of")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid as_of date, expected YYYY-MM-DD")
+		return
+	}
+	filter.LegalAsOf = asOf
+
+	if err := getValueFilters(r, &filter); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if name, ok := getStatRanges(r, &filter); !ok {
+		writeError(w, http.StatusBadRequest, "invalid "+name+", expected a whole number")
+		return
+	}
+
+	if err := getCardSort(r, &filter); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	facets, err := data.ParseFacets(query.Get("facets"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mode, ok := getRenderParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, invalidRenderMessage)
+		return
+	}
+
 	// Cap limit at 100
 	if filter.Limit > 100 {
 		filter.Limit = 100
 	}
 
-	cards, total := h.store.SearchCards(filter)
+	h.writeCardPage(w, r, filter, facets, mode)
+}
+
+// writeCardPage searches for a page of cards and writes it, linking to the
+// neighbouring pages. Text queries come back ranked, with a relevance score
+// on each card.
+func (h *Handler) writeCardPage(w http.ResponseWriter, r *http.Request, filter data.CardFilter, facets []data.Facet, mode render.Mode) {
+	page, err := h.store.SearchCardsPage(filter, facets)
+	if err != nil {
+		writeCursorError(w, err)
+		return
+	}
+	observability.SetCacheAttributes(r.Context(), observability.CacheCardSearch, page.Cached)
+
+	cards := page.Cards
 	if cards == nil {
 		// Ensure we send back an empty array instead of null
-		cards = make([]*domain.Card, 0)
+		cards = make([]domain.ScoredCard, 0)
+	}
+	if mode != render.ModeRaw {
+		for i := range cards {
+			cards[i].Card = h.store.RenderCard(cards[i].Card, mode)
+		}
 	}
 
+	setPageLinks(w, r, page.Next, page.Prev)
 	writeJSON(w, http.StatusOK, PaginatedResponse{
-		Data:   cards,
-		Total:  total,
-		Limit:  filter.Limit,
-		Offset: filter.Offset,
+		Data:       cards,
+		Total:      page.Total,
+		Limit:      filter.Limit,
+		Offset:     page.Offset,
+		Facets:     page.Facets,
+		NextCursor: page.Next,
+		PrevCursor: page.Prev,
 	})
 }
 
+// SearchCards returns the cards matching a query language expression.
+func (h *Handler) SearchCards(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	expr, err := query.Parse(q.Get("query"))
+	if err != nil {
+		var syntaxErr *query.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			writeJSON(w, http.StatusBadRequest, QueryErrorResponse{
+				Error:    syntaxErr.Error(),
+				Position: syntaxErr.Pos + 1,
+				Token:    syntaxErr.Token,
+			})
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mode, ok := getRenderParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, invalidRenderMessage)
+		return
+	}
+
+	filter := data.CardFilter{
+		Query:         expr,
+		CollapseFaces: q.Get("collapse_faces") != "false",
+		Limit:         getIntParam(r, "limit", 50),
+		Offset:        getIntParam(r, "offset", 0),
+		Cursor:        q.Get("cursor"),
+	}
+
+	if err := getCardSort(r, &filter); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Cap limit at 100
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	h.writeCardPage(w, r, filter, nil, mode)
+}
+
+// AutocompleteCards returns card names starting with a prefix, for search
+// boxes.
+func (h *Handler) AutocompleteCards(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+	if prefix == "" {
+		writeError(w, http.StatusBadRequest, "prefix required")
+		return
+	}
+
+	limit := getIntParam(r, "limit", 10)
+	if limit > 50 {
+		limit = 50
+	}
+
+	names := h.store.AutocompleteCardNames(prefix, limit)
+	if names == nil {
+		// Ensure we send back an empty array instead of null
+		names = make([]string, 0)
+	}
+
+	writeJSON(w, http.StatusOK, names)
+}
+
 // GetCard returns a single card by ID.
 func (h *Handler) GetCard(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
@@ -176,19 +493,64 @@ func (h *Handler) GetCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	card := h.store.GetCardByID(id)
+	mode, ok := getRenderParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, invalidRenderMessage)
+		return
+	}
+
+	card, fuzzy := h.resolveCard(w, r)
 	if card == nil {
-		// Try by name
-		cards := h.store.GetCardsByName(id)
-		if len(cards) == 0 {
-			writeError(w, http.StatusNotFound, "card not found")
-			return
+		return
+	}
+
+	// Embed the card's other faces, if any
+	type CardWithFaces struct {
+		*domain.Card
+		OtherFaces []domain.CardSummary `json:"other_faces,omitempty"`
+		FuzzyMatch bool                 `json:"fuzzy_match,omitempty"`
+	}
+
+	response := CardWithFaces{Card: h.store.RenderCard(card, mode), FuzzyMatch: fuzzy}
+	for _, face := range h.store.GetOtherFaces(card.UniqueID) {
+		response.OtherFaces = append(response.OtherFaces, face.Summary())
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// resolveCard resolves the card named by the id path value, which may be a
+// unique ID, a printing ID or a name, narrowed by the pitch and color
+// parameters. Names fall back to the closest card name. When the reference
+// matches no card or several, it writes the 404 or 300 response and returns
+// nil. It also reports whether the name match was inexact.
+func (h *Handler) resolveCard(w http.ResponseWriter, r *http.Request) (*domain.Card, bool) {
+	id := r.PathValue("id")
+	query := r.URL.Query()
+
+	match := h.store.ResolveCard(id, query.Get("pitch"), query.Get("color"))
+	switch {
+	case match.Card != nil:
+		return match.Card, match.Fuzzy
+
+	case len(match.Candidates) > 0:
+		candidates := make([]domain.CardSummary, len(match.Candidates))
+		for i, card := range match.Candidates {
+			candidates[i] = card.Summary()
 		}
-		// Return first match if searching by name
-		card = cards[0]
+		writeJSON(w, http.StatusMultipleChoices, MultipleChoicesResponse{
+			Error:      fmt.Sprintf("%d cards are named %q, select one with pitch or color", len(candidates), candidates[0].Name),
+			Candidates: candidates,
+		})
+
+	default:
+		writeJSON(w, http.StatusNotFound, ErrorResponse{
+			Error:       "card not found",
+			Suggestions: h.store.SuggestCardNames(id, 5),
+		})
 	}
 
-	writeJSON(w, http.StatusOK, card)
+	return nil, false
 }
 
 // ListSets returns sets, optionally filtered by query parameters.
@@ -196,18 +558,37 @@ func (h *Handler) ListSets(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 
 	filter := data.SetFilter{
-		Name:  query.Get("name"),
-		ID:    query.Get("id"),
-		Query: query.Get("q"),
+		Name:   query.Get("name"),
+		ID:     query.Get("id"),
+		Query:  query.Get("q"),
+		Limit:  getIntParam(r, "limit", 0),
+		Cursor: query.Get("cursor"),
 	}
 
-	// If no filters provided, return all sets
-	if filter.Name == "" && filter.ID == "" && filter.Query == "" {
-		writeJSON(w, http.StatusOK, h.store.Sets)
+	var err error
+	if filter.Sort, err = data.ParseSetSort(query.Get("sort")); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.Order, err = data.ParseSetOrder(filter.Sort, query.Get("order")); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	sets := h.store.SearchSets(filter)
+	// An empty filter matches every set. Sets are only paged with a limit,
+	// and the pages are linked from the Link header.
+	page, err := h.store.SearchSetsPage(filter)
+	if err != nil {
+		writeCursorError(w, err)
+		return
+	}
+
+	sets := page.Sets
+	if sets == nil {
+		// Ensure we send back an empty array instead of null
+		sets = make([]*domain.Set, 0)
+	}
+	setPageLinks(w, r, page.Next, page.Prev)
 	writeJSON(w, http.StatusOK, sets)
 }
 
@@ -219,6 +600,12 @@ func (h *Handler) GetSet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	mode, ok := getRenderParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, invalidRenderMessage)
+		return
+	}
+
 	set := h.store.GetSetByID(id)
 	if set == nil {
 		writeError(w, http.StatusNotFound, "set not found")
@@ -235,7 +622,7 @@ func (h *Handler) GetSet(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, SetWithCards{
 		Set:   set,
-		Cards: cards,
+		Cards: h.renderCards(cards, mode),
 	})
 }
 
@@ -274,9 +661,8 @@ func (h *Handler) GetCardLegality(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	card := h.store.GetCardByID(id)
+	card, _ := h.resolveCard(w, r)
 	if card == nil {
-		writeError(w, http.StatusNotFound, "card not found")
 		return
 	}
 
@@ -289,20 +675,404 @@ func (h *Handler) GetCardLegality(w http.ResponseWriter, r *http.Request) {
 		domain.FormatUPF,
 	}
 
+	asOf, err := getDateParam(r, "as_of")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid as_of date, expected YYYY-MM-DD")
+		return
+	}
+
 	legalities := make([]domain.Legality, len(formats))
 	for i, format := range formats {
-		legalities[i] = card.GetLegality(format)
+		if asOf.IsZero() {
+			legalities[i] = h.store.GetLegality(card, format)
+		} else {
+			legalities[i] = h.store.GetLegalityAt(card, format, asOf)
+		}
 	}
 
 	type LegalityResponse struct {
-		CardID     string            `json:"card_id"`
-		CardName   string            `json:"card_name"`
-		Legalities []domain.Legality `json:"legalities"`
+		CardID     string                 `json:"card_id"`
+		CardName   string                 `json:"card_name"`
+		AsOf       string                 `json:"as_of,omitempty"`
+		Legalities []domain.Legality      `json:"legalities"`
+		History    domain.LegalityHistory `json:"history,omitempty"`
 	}
 
-	writeJSON(w, http.StatusOK, LegalityResponse{
+	response := LegalityResponse{
 		CardID:     card.UniqueID,
 		CardName:   card.Name,
 		Legalities: legalities,
+		History:    h.store.GetLegalityHistory(card.UniqueID),
+	}
+	if !asOf.IsZero() {
+		response.AsOf = asOf.Format(time.DateOnly)
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// ListFormats returns every format with its current ban list.
+func (h *Handler) ListFormats(w http.ResponseWriter, r *http.Request) {
+	asOf, err := getDateParam(r, "as_of")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid as_of date, expected YYYY-MM-DD")
+		return
+	}
+	if asOf.IsZero() {
+		asOf = time.Now().UTC()
+	}
+
+	banLists := make([]*domain.BanList, len(domain.Formats))
+	for i, format := range domain.Formats {
+		banLists[i] = h.store.GetBanList(format, asOf)
+	}
+
+	writeJSON(w, http.StatusOK, banLists)
+}
+
+// GetFormat returns a single format with its current ban list.
+func (h *Handler) GetFormat(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("format")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "format required")
+		return
+	}
+
+	format, ok := domain.GetFormatInfo(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "format not found")
+		return
+	}
+
+	asOf, err := getDateParam(r, "as_of")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid as_of date, expected YYYY-MM-DD")
+		return
+	}
+	if asOf.IsZero() {
+		asOf = time.Now().UTC()
+	}
+
+	writeJSON(w, http.StatusOK, h.store.GetBanList(format, asOf))
+}
+
+// ListLegalityChanges returns legality events announced or taking effect in a
+// date window, including scheduled changes that are not in effect yet.
+func (h *Handler) ListLegalityChanges(w http.ResponseWriter, r *http.Request) {
+	since, err := getDateParam(r, "since")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid since date, expected YYYY-MM-DD")
+		return
+	}
+	if since.IsZero() {
+		since = time.Now().UTC().AddDate(0, 0, -90)
+	}
+
+	until, err := getDateParam(r, "until")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid until date, expected YYYY-MM-DD")
+		return
+	}
+	if !until.IsZero() && until.Before(since) {
+		writeError(w, http.StatusBadRequest, "until must not be before since")
+		return
+	}
+
+	filter := data.LegalityChangeFilter{
+		Since: since,
+		Until: until,
+	}
+
+	if id := r.URL.Query().Get("format"); id != "" {
+		format, ok := domain.GetFormatInfo(id)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "unknown format")
+			return
+		}
+		filter.Format = format.ID
+	}
+
+	changes := h.store.GetLegalityChanges(filter)
+	if changes == nil {
+		changes = make([]*domain.LegalityChange, 0)
+	}
+
+	writeJSON(w, http.StatusOK, changes)
+}
+
+// ListCardReferences returns the cards a card refers to.
+func (h *Handler) ListCardReferences(w http.ResponseWriter, r *http.Request) {
+	h.listRelatedCards(w, r, h.store.GetReferencedCards)
+}
+
+// ListCardReferencedBy returns the cards that refer to a card.
+func (h *Handler) ListCardReferencedBy(w http.ResponseWriter, r *http.Request) {
+	h.listRelatedCards(w, r, h.store.GetReferencingCards)
+}
+
+func (h *Handler) listRelatedCards(w http.ResponseWriter, r *http.Request, related func(string) []*domain.Card) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "card ID required")
+		return
+	}
+
+	card, _ := h.resolveCard(w, r)
+	if card == nil {
+		return
+	}
+
+	cards := related(card.UniqueID)
+	summaries := make([]domain.CardSummary, len(cards))
+	for i, c := range cards {
+		summaries[i] = c.Summary()
+	}
+
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+// GetCardGraph returns the neighbourhood of a card in the reference graph.
+func (h *Handler) GetCardGraph(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "card ID required")
+		return
+	}
+
+	card, _ := h.resolveCard(w, r)
+	if card == nil {
+		return
+	}
+
+	depth := getIntParam(r, "depth", 1)
+	if depth < 1 {
+		depth = 1
+	}
+	// Cap depth at 3, the graph grows quickly past that
+	if depth > 3 {
+		depth = 3
+	}
+
+	direction := domain.GraphDirection(r.URL.Query().Get("direction"))
+	switch direction {
+	case "":
+		direction = domain.GraphBoth
+	case domain.GraphReferences, domain.GraphReferencedBy, domain.GraphBoth:
+	default:
+		writeError(w, http.StatusBadRequest, "direction must be one of references, referenced_by, both")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.store.GetCardGraph(card, depth, direction))
+}
+
+// GetCardFaces returns the chain of faces a card belongs to.
+func (h *Handler) GetCardFaces(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "card ID required")
+		return
+	}
+
+	card, _ := h.resolveCard(w, r)
+	if card == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.store.GetCardFaces(card.UniqueID))
+}
+
+// ListPrintings returns a list of printings matching query parameters.
+func (h *Handler) ListPrintings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := data.PrintingFilter{
+		SetID:        query.Get("set"),
+		Rarity:       query.Get("rarity"),
+		Foiling:      query.Get("foiling"),
+		Edition:      query.Get("edition"),
+		ArtVariation: query.Get("art_variation"),
+		Limit:        getIntParam(r, "limit", 50),
+		Offset:       getIntParam(r, "offset", 0),
+	}
+
+	// Cap limit at 100
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	printings, total := h.store.SearchPrintings(filter)
+	if printings == nil {
+		// Ensure we send back an empty array instead of null
+		printings = make([]*domain.CardPrinting, 0)
+	}
+	printings, ok := h.describePrintings(r, printings)
+	if !ok {
+		writeError(w, http.StatusBadRequest, invalidRenderMessage)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PaginatedResponse{
+		Data:   printings,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	})
+}
+
+// GetPrinting returns the printings matching a unique ID or collector ID.
+func (h *Handler) GetPrinting(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "printing ID required")
+		return
+	}
+
+	printings := h.store.GetPrintings(id)
+	if len(printings) == 0 {
+		writeError(w, http.StatusNotFound, "printing not found")
+		return
+	}
+
+	printings, ok := h.describePrintings(r, printings)
+	if !ok {
+		writeError(w, http.StatusBadRequest, invalidRenderMessage)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, printings)
+}
+
+// describePrintings adds human-readable code names to the printings when the
+// request asks for them with names=true, and renders their cards' text when it
+// sets render. It reports false for an invalid render mode.
+func (h *Handler) describePrintings(r *http.Request, printings []*domain.CardPrinting) ([]*domain.CardPrinting, bool) {
+	mode, ok := getRenderParam(r)
+	if !ok {
+		return nil, false
+	}
+
+	names := r.URL.Query().Get("names") == "true"
+	if !names && mode == render.ModeRaw {
+		return printings, true
+	}
+
+	described := make([]*domain.CardPrinting, len(printings))
+	for i, printing := range printings {
+		if names {
+			printing = h.store.DescribePrinting(printing)
+		} else {
+			copied := *printing
+			printing = &copied
+		}
+		printing.Card = h.store.RenderCard(printing.Card, mode)
+		described[i] = printing
+	}
+	return described, true
+}
+
+// ListRarities returns all rarity codes.
+func (h *Handler) ListRarities(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.store.Rarities)
+}
+
+// ListFoilings returns all foiling codes.
+func (h *Handler) ListFoilings(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.store.Foilings)
+}
+
+// ListEditions returns all edition codes.
+func (h *Handler) ListEditions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.store.Editions)
+}
+
+// ListArtVariations returns all art variation codes.
+func (h *Handler) ListArtVariations(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.store.ArtVariations)
+}
+
+// ListArtists returns the artists whose name matches the q parameter, or every
+// artist.
+func (h *Handler) ListArtists(w http.ResponseWriter, r *http.Request) {
+	artists := h.store.SearchArtists(r.URL.Query().Get("q"))
+	if artists == nil {
+		// Ensure we send back an empty array instead of null
+		artists = make([]*domain.Artist, 0)
+	}
+
+	writeJSON(w, http.StatusOK, artists)
+}
+
+// GetArtist returns an artist with their printing counts per set.
+func (h *Handler) GetArtist(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "artist name required")
+		return
+	}
+
+	artist := h.store.GetArtist(name)
+	if artist == nil {
+		writeError(w, http.StatusNotFound, "artist not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, artist)
+}
+
+// ListArtistPrintings returns the printings an artist is credited on.
+func (h *Handler) ListArtistPrintings(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "artist name required")
+		return
+	}
+
+	artist := h.store.GetArtist(name)
+	if artist == nil {
+		writeError(w, http.StatusNotFound, "artist not found")
+		return
+	}
+
+	filter := data.ArtistPrintingFilter{
+		SetID:  r.URL.Query().Get("set"),
+		Limit:  getIntParam(r, "limit", 50),
+		Offset: getIntParam(r, "offset", 0),
+	}
+
+	// Cap limit at 100
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	printings, total := h.store.GetArtistPrintings(artist.Name, filter)
+	if printings == nil {
+		// Ensure we send back an empty array instead of null
+		printings = make([]*domain.CardPrinting, 0)
+	}
+	printings, ok := h.describePrintings(r, printings)
+	if !ok {
+		writeError(w, http.StatusBadRequest, invalidRenderMessage)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PaginatedResponse{
+		Data:   printings,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
 	})
 }
+
+// GetChangelog returns the cards added, removed and changed between the
+// previously served data version and the current one.
+func (h *Handler) GetChangelog(w http.ResponseWriter, r *http.Request) {
+	changelog := h.provider.Changelog()
+	if changelog == nil {
+		writeError(w, http.StatusNotFound, "no changelog recorded for data version "+h.store.Version+": "+
+			"changelogs are recorded when a reload changes the data, and kept across restarts only when CHANGELOG_FILE is set")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, changelog)
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/oleiade/goagain/internal/data"
+)
+
+// testCards are the pitch variants of an attack and a card named once.
+const testCards = `[
+	{"unique_id": "press-red", "name": "Lunging Press", "pitch": "1", "color": "Red", "types": ["Ninja", "Action", "Attack"]},
+	{"unique_id": "press-yellow", "name": "Lunging Press", "pitch": "2", "color": "Yellow", "types": ["Ninja", "Action", "Attack"]},
+	{"unique_id": "press-blue", "name": "Lunging Press", "pitch": "3", "color": "Blue", "types": ["Ninja", "Action", "Attack"]},
+	{"unique_id": "plating", "name": "Tectonic Plating", "types": ["Guardian", "Equipment", "Chest"]}
+]`
+
+// newTestHandler serves the given card.json, with the other data files copied
+// from the embedded data, through the handler's routes.
+func newTestHandler(t *testing.T, cardJSON string) (http.Handler, *data.Provider, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.CopyFS(dir, os.DirFS("../data/english")); err != nil {
+		t.Fatalf("copying data files: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "card.json"), []byte(cardJSON), 0o644); err != nil {
+		t.Fatalf("writing card.json: %v", err)
+	}
+
+	provider, err := data.NewProvider(data.ProviderConfig{Dir: dir}, nil)
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	h := NewHandler(provider, "", "")
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/cards", h.bind((*Handler).ListCards))
+	mux.HandleFunc("GET /v1/cards/{id}", h.bind((*Handler).GetCard))
+	mux.HandleFunc("GET /v1/sets", h.bind((*Handler).ListSets))
+
+	return mux, provider, dir
+}
+
+func get(t *testing.T, handler http.Handler, target string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestGetCardMultipleChoices(t *testing.T) {
+	handler, _, _ := newTestHandler(t, testCards)
+
+	rec := get(t, handler, "/v1/cards/lunging%20press")
+	if rec.Code != http.StatusMultipleChoices {
+		t.Fatalf("GET ambiguous name = %d, want %d: %s", rec.Code, http.StatusMultipleChoices, rec.Body)
+	}
+	var choices MultipleChoicesResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &choices); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(choices.Candidates) != 3 || choices.Error == "" {
+		t.Errorf("Multiple choices = %+v, want the three pitch variants", choices)
+	}
+
+	for target, want := range map[string]string{
+		"/v1/cards/lunging%20press?pitch=2":    "press-yellow",
+		"/v1/cards/lunging%20press?color=blue": "press-blue",
+		"/v1/cards/tectonic%20plating":         "plating",
+	} {
+		rec := get(t, handler, target)
+		var card struct {
+			UniqueID string `json:"unique_id"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &card); rec.Code != http.StatusOK || err != nil || card.UniqueID != want {
+			t.Errorf("GET %s = %d %s, want %s", target, rec.Code, card.UniqueID, want)
+		}
+	}
+
+	if rec := get(t, handler, "/v1/cards/no%20such%20card"); rec.Code != http.StatusNotFound {
+		t.Errorf("GET unknown card = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+// pageLink returns the URL a Link header gives for a relation.
+func pageLink(rec *httptest.ResponseRecorder, rel string) string {
+	pattern := regexp.MustCompile(`<([^>]*)>; rel="` + rel + `"`)
+	m := pattern.FindStringSubmatch(rec.Header().Get("Link"))
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+func TestListCardsCursor(t *testing.T) {
+	handler, provider, dir := newTestHandler(t, testCards)
+
+	first := get(t, handler, "/v1/cards?limit=1&offset=0&sort=name")
+	if first.Code != http.StatusOK {
+		t.Fatalf("GET first page = %d: %s", first.Code, first.Body)
+	}
+	next := pageLink(first, "next")
+	if !strings.Contains(next, "cursor=") || strings.Contains(next, "offset=") {
+		t.Fatalf("Link next = %q, want a cursor in place of the offset", next)
+	}
+	if prev := pageLink(first, "prev"); prev != "" {
+		t.Errorf("Link prev on the first page = %q, want none", prev)
+	}
+
+	second := get(t, handler, next)
+	if second.Code != http.StatusOK {
+		t.Fatalf("GET %s = %d: %s", next, second.Code, second.Body)
+	}
+	if pageLink(second, "prev") == "" || pageLink(second, "next") == "" {
+		t.Errorf("Link on the second page = %q, want next and prev", second.Header().Get("Link"))
+	}
+	var page PaginatedResponse
+	if err := json.Unmarshal(second.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if page.NextCursor == "" || page.PrevCursor == "" {
+		t.Errorf("Second page cursors = %q, %q, want both", page.NextCursor, page.PrevCursor)
+	}
+
+	// Cursors only resume the search and sort order they were issued for
+	for target, want := range map[string]int{
+		"/v1/cards?limit=1&cursor=garbage":                                 http.StatusBadRequest,
+		"/v1/cards?limit=1&sort=name&name=press&cursor=" + page.NextCursor: http.StatusBadRequest,
+		"/v1/cards?limit=1&sort=pitch&cursor=" + page.NextCursor:           http.StatusBadRequest,
+	} {
+		if rec := get(t, handler, target); rec.Code != want {
+			t.Errorf("GET %s = %d, want %d", target, rec.Code, want)
+		}
+	}
+
+	// and expire once the data changes
+	cards := strings.Replace(testCards, `"Tectonic Plating"`, `"Tectonic Plating II"`, 1)
+	if err := os.WriteFile(filepath.Join(dir, "card.json"), []byte(cards), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if rec := get(t, handler, next); rec.Code != http.StatusGone {
+		t.Errorf("GET stale cursor = %d, want %d: %s", rec.Code, http.StatusGone, rec.Body)
+	}
+}
+
+func TestListSetsLinks(t *testing.T) {
+	handler, _, _ := newTestHandler(t, testCards)
+
+	rec := get(t, handler, "/v1/sets?limit=1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET sets = %d: %s", rec.Code, rec.Body)
+	}
+	next := pageLink(rec, "next")
+	if !strings.Contains(next, "cursor=") {
+		t.Fatalf("Link next = %q, want a cursor", next)
+	}
+	if rec := get(t, handler, next); rec.Code != http.StatusOK || pageLink(rec, "prev") == "" {
+		t.Errorf("GET %s = %d with Link %q, want a prev link", next, rec.Code, rec.Header().Get("Link"))
+	}
+}
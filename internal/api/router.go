@@ -14,6 +14,7 @@ import (
 
 	"github.com/oleiade/goagain/internal/data"
 	"github.com/oleiade/goagain/internal/observability"
+	"github.com/oleiade/goagain/internal/server"
 	"golang.org/x/time/rate"
 )
 
@@ -33,6 +34,9 @@ type Config struct {
 	TrustedProxies []*net.IPNet
 	APIBaseURL     string
 	MCPBaseURL     string
+
+	// AdminToken authenticates admin endpoints; they are disabled when empty
+	AdminToken string
 }
 
 // LoadConfig loads configuration from environment variables.
@@ -77,35 +81,61 @@ func LoadConfig() Config {
 		config.MCPBaseURL = strings.TrimSuffix(mcpURL, "/")
 	}
 
+	config.AdminToken = os.Getenv("ADMIN_TOKEN")
+
 	return config
 }
 
 // NewRouter creates a new HTTP router with all API routes registered.
-func NewRouter(store *data.Store, logger *slog.Logger, metrics *observability.Metrics, obsConfig observability.Config) http.Handler {
+func NewRouter(provider *data.Provider, logger *slog.Logger, metrics *observability.Metrics, obsConfig observability.Config) http.Handler {
 	config := LoadConfig()
 
 	mux := http.NewServeMux()
-	h := NewHandler(store, config.APIBaseURL, config.MCPBaseURL)
+	h := NewHandler(provider, config.APIBaseURL, config.MCPBaseURL)
 
 	// Root - Landing page / API info (unversioned)
-	mux.HandleFunc("GET /", h.Index)
+	mux.HandleFunc("GET /", h.bind((*Handler).Index))
 
 	// Operational endpoints (unversioned)
-	mux.HandleFunc("GET /health", h.Health)
+	mux.HandleFunc("GET /health", h.bind((*Handler).Health))
 	mux.HandleFunc("GET /openapi.yaml", serveOpenAPI)
 	mux.HandleFunc("GET /openapi", serveOpenAPI)
 	mux.HandleFunc("GET /docs", serveDocs)
 	mux.HandleFunc("GET /static/tailwind.min.css", serveTailwindCSS)
 
 	// API v1 endpoints
-	mux.HandleFunc("GET /v1/cards", h.ListCards)
-	mux.HandleFunc("GET /v1/cards/{id}", h.GetCard)
-	mux.HandleFunc("GET /v1/cards/{id}/legality", h.GetCardLegality)
-	mux.HandleFunc("GET /v1/sets", h.ListSets)
-	mux.HandleFunc("GET /v1/sets/{id}", h.GetSet)
-	mux.HandleFunc("GET /v1/keywords", h.ListKeywords)
-	mux.HandleFunc("GET /v1/keywords/{name}", h.GetKeyword)
-	mux.HandleFunc("GET /v1/abilities", h.ListAbilities)
+	mux.HandleFunc("GET /v1/cards", h.bind((*Handler).ListCards))
+	mux.HandleFunc("GET /v1/cards/search", h.bind((*Handler).SearchCards))
+	mux.HandleFunc("GET /v1/cards/autocomplete", h.bind((*Handler).AutocompleteCards))
+	mux.HandleFunc("GET /v1/cards/{id}", h.bind((*Handler).GetCard))
+	mux.HandleFunc("GET /v1/cards/{id}/legality", h.bind((*Handler).GetCardLegality))
+	mux.HandleFunc("GET /v1/cards/{id}/references", h.bind((*Handler).ListCardReferences))
+	mux.HandleFunc("GET /v1/cards/{id}/referenced-by", h.bind((*Handler).ListCardReferencedBy))
+	mux.HandleFunc("GET /v1/cards/{id}/graph", h.bind((*Handler).GetCardGraph))
+	mux.HandleFunc("GET /v1/cards/{id}/faces", h.bind((*Handler).GetCardFaces))
+	mux.HandleFunc("GET /v1/printings", h.bind((*Handler).ListPrintings))
+	mux.HandleFunc("GET /v1/printings/{id}", h.bind((*Handler).GetPrinting))
+	mux.HandleFunc("GET /v1/artists", h.bind((*Handler).ListArtists))
+	mux.HandleFunc("GET /v1/artists/{name}", h.bind((*Handler).GetArtist))
+	mux.HandleFunc("GET /v1/artists/{name}/printings", h.bind((*Handler).ListArtistPrintings))
+	mux.HandleFunc("GET /v1/sets", h.bind((*Handler).ListSets))
+	mux.HandleFunc("GET /v1/sets/{id}", h.bind((*Handler).GetSet))
+	mux.HandleFunc("GET /v1/keywords", h.bind((*Handler).ListKeywords))
+	mux.HandleFunc("GET /v1/keywords/{name}", h.bind((*Handler).GetKeyword))
+	mux.HandleFunc("GET /v1/abilities", h.bind((*Handler).ListAbilities))
+	mux.HandleFunc("GET /v1/rarities", h.bind((*Handler).ListRarities))
+	mux.HandleFunc("GET /v1/foilings", h.bind((*Handler).ListFoilings))
+	mux.HandleFunc("GET /v1/editions", h.bind((*Handler).ListEditions))
+	mux.HandleFunc("GET /v1/art-variations", h.bind((*Handler).ListArtVariations))
+	mux.HandleFunc("GET /v1/formats", h.bind((*Handler).ListFormats))
+	mux.HandleFunc("GET /v1/formats/{format}", h.bind((*Handler).GetFormat))
+	mux.HandleFunc("GET /v1/legality/changes", h.bind((*Handler).ListLegalityChanges))
+	mux.HandleFunc("GET /v1/changelog", h.bind((*Handler).GetChangelog))
+
+	// Admin endpoints (unversioned), only served when a token is configured
+	if config.AdminToken != "" {
+		mux.HandleFunc("POST /admin/reload", server.ReloadHandler(provider, config.AdminToken, logger))
+	}
 
 	// Build middleware chain (applied in reverse order)
 	handler := http.Handler(mux)
@@ -0,0 +1,134 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+func (s *Store) loadArtists() error {
+	data, err := s.readFile("artist.json")
+	if err != nil {
+		return fmt.Errorf("reading artist.json: %w", err)
+	}
+
+	var artists []*domain.Artist
+	if err := json.Unmarshal(data, &artists); err != nil {
+		return fmt.Errorf("parsing artist.json: %w", err)
+	}
+
+	s.Artists = artists
+	for _, artist := range artists {
+		s.ArtistsByName[strings.ToLower(artist.Name)] = artist
+	}
+
+	return nil
+}
+
+// indexArtists attaches printings to the artists credited on them and counts
+// each artist's printings, cards and sets. Artists credited on a printing but
+// missing from artist.json are added to the index.
+func (s *Store) indexArtists() {
+	cards := make(map[string]map[string]bool)
+	sets := make(map[string]map[string]int)
+
+	for _, printing := range s.Printings {
+		for _, name := range printing.Artists {
+			key := strings.ToLower(name)
+
+			artist := s.ArtistsByName[key]
+			if artist == nil {
+				artist = &domain.Artist{Name: name}
+				s.Artists = append(s.Artists, artist)
+				s.ArtistsByName[key] = artist
+			}
+
+			s.PrintingsByArtist[key] = append(s.PrintingsByArtist[key], printing)
+			artist.PrintingCount++
+
+			if cards[key] == nil {
+				cards[key] = make(map[string]bool)
+				sets[key] = make(map[string]int)
+			}
+			cards[key][printing.Card.UniqueID] = true
+			sets[key][printing.SetID]++
+		}
+	}
+
+	for key, artist := range s.ArtistsByName {
+		artist.CardCount = len(cards[key])
+		artist.Sets = make([]domain.ArtistSetCount, 0, len(sets[key]))
+		for setID, count := range sets[key] {
+			setCount := domain.ArtistSetCount{SetID: setID, Printings: count}
+			if set := s.SetsByID[setID]; set != nil {
+				setCount.SetName = set.Name
+			}
+			artist.Sets = append(artist.Sets, setCount)
+		}
+		slices.SortFunc(artist.Sets, func(a, b domain.ArtistSetCount) int {
+			return strings.Compare(a.SetID, b.SetID)
+		})
+	}
+
+	slices.SortStableFunc(s.Artists, func(a, b *domain.Artist) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+}
+
+// GetArtist returns an artist by name (case-insensitive).
+func (s *Store) GetArtist(name string) *domain.Artist {
+	return s.ArtistsByName[strings.ToLower(name)]
+}
+
+// SearchArtists returns the artists whose name contains the query
+// (case-insensitive), or every artist when the query is empty.
+func (s *Store) SearchArtists(query string) []*domain.Artist {
+	query = strings.ToLower(query)
+
+	var results []*domain.Artist
+	for _, artist := range s.Artists {
+		if query == "" || strings.Contains(strings.ToLower(artist.Name), query) {
+			results = append(results, artist)
+		}
+	}
+
+	return results
+}
+
+// ArtistPrintingFilter defines filtering criteria for an artist's printings.
+type ArtistPrintingFilter struct {
+	SetID  string
+	Limit  int
+	Offset int
+}
+
+// GetArtistPrintings returns the paginated printings an artist is credited on,
+// optionally restricted to a set, along with the total number of matches.
+func (s *Store) GetArtistPrintings(name string, filter ArtistPrintingFilter) ([]*domain.CardPrinting, int) {
+	var results []*domain.CardPrinting
+	for _, printing := range s.PrintingsByArtist[strings.ToLower(name)] {
+		if filter.SetID != "" && !strings.EqualFold(printing.SetID, filter.SetID) {
+			continue
+		}
+		results = append(results, printing)
+	}
+
+	total := len(results)
+
+	// Apply pagination
+	if filter.Offset > 0 {
+		if filter.Offset >= len(results) {
+			return nil, total // Page is out of bounds
+		}
+		results = results[filter.Offset:]
+	}
+
+	if filter.Limit > 0 && len(results) > filter.Limit {
+		results = results[:filter.Limit]
+	}
+
+	return results, total
+}
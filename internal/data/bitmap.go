@@ -0,0 +1,197 @@
+package data
+
+import (
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// Bitmap is an immutable set of card ordinals. Like a roaring bitmap
+// container, it holds few ordinals as a sorted list and many as bit words,
+// whichever takes less memory, so the many small keyword and set buckets stay
+// compact while class and format buckets intersect word by word.
+type Bitmap struct {
+	sparse []uint32 // sorted ordinals, when dense is nil
+	dense  []uint64 // bit words, without trailing zero words
+	count  int
+}
+
+// newBitmap returns a bitmap of sorted, distinct ordinals.
+func newBitmap(ordinals []uint32) *Bitmap {
+	if len(ordinals) == 0 {
+		return &Bitmap{}
+	}
+
+	words := int(ordinals[len(ordinals)-1])/64 + 1
+	if !preferDense(len(ordinals), words) {
+		return &Bitmap{sparse: ordinals, count: len(ordinals)}
+	}
+
+	dense := make([]uint64, words)
+	for _, ordinal := range ordinals {
+		dense[ordinal/64] |= 1 << (ordinal % 64)
+	}
+	return &Bitmap{dense: dense, count: len(ordinals)}
+}
+
+// fromWords returns a bitmap of bit words, which it may keep.
+func fromWords(words []uint64) *Bitmap {
+	for len(words) > 0 && words[len(words)-1] == 0 {
+		words = words[:len(words)-1]
+	}
+
+	count := 0
+	for _, word := range words {
+		count += bits.OnesCount64(word)
+	}
+	if preferDense(count, len(words)) {
+		return &Bitmap{dense: words, count: count}
+	}
+
+	sparse := make([]uint32, 0, count)
+	for i, word := range words {
+		for word != 0 {
+			sparse = append(sparse, uint32(i*64+bits.TrailingZeros64(word)))
+			word &= word - 1
+		}
+	}
+	return &Bitmap{sparse: sparse, count: count}
+}
+
+// preferDense reports whether count ordinals take less memory as words bit
+// words than as a list.
+func preferDense(count, words int) bool {
+	return count*4 > words*8
+}
+
+// Len returns the number of ordinals in the bitmap.
+func (b *Bitmap) Len() int {
+	return b.count
+}
+
+// Contains reports whether the bitmap holds an ordinal.
+func (b *Bitmap) Contains(ordinal int) bool {
+	if b.dense == nil {
+		_, found := slices.BinarySearch(b.sparse, uint32(ordinal))
+		return found
+	}
+	i := ordinal / 64
+	return i < len(b.dense) && b.dense[i]&(1<<(ordinal%64)) != 0
+}
+
+// All yields the ordinals in ascending order.
+func (b *Bitmap) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if b.dense == nil {
+			for _, ordinal := range b.sparse {
+				if !yield(int(ordinal)) {
+					return
+				}
+			}
+			return
+		}
+
+		for i, word := range b.dense {
+			for word != 0 {
+				if !yield(i*64 + bits.TrailingZeros64(word)) {
+					return
+				}
+				word &= word - 1
+			}
+		}
+	}
+}
+
+// And returns the ordinals in both bitmaps.
+func (b *Bitmap) And(other *Bitmap) *Bitmap {
+	if b.dense != nil && other.dense != nil {
+		words := make([]uint64, min(len(b.dense), len(other.dense)))
+		for i := range words {
+			words[i] = b.dense[i] & other.dense[i]
+		}
+		return fromWords(words)
+	}
+
+	// Probe the dense side, or the larger list, with the smaller list
+	small, large := b, other
+	if small.dense != nil || (large.dense == nil && large.count < small.count) {
+		small, large = large, small
+	}
+	var ordinals []uint32
+	for _, ordinal := range small.sparse {
+		if large.Contains(int(ordinal)) {
+			ordinals = append(ordinals, ordinal)
+		}
+	}
+	return &Bitmap{sparse: ordinals, count: len(ordinals)}
+}
+
+// Or returns the ordinals in either bitmap.
+func (b *Bitmap) Or(other *Bitmap) *Bitmap {
+	if b.dense == nil && other.dense == nil {
+		merged := make([]uint32, 0, b.count+other.count)
+		i, j := 0, 0
+		for i < len(b.sparse) && j < len(other.sparse) {
+			switch x, y := b.sparse[i], other.sparse[j]; {
+			case x < y:
+				merged = append(merged, x)
+				i++
+			case x > y:
+				merged = append(merged, y)
+				j++
+			default:
+				merged = append(merged, x)
+				i, j = i+1, j+1
+			}
+		}
+		merged = append(merged, b.sparse[i:]...)
+		merged = append(merged, other.sparse[j:]...)
+		return newBitmap(merged)
+	}
+
+	words := b.words()
+	if b.dense != nil {
+		words = slices.Clone(words)
+	}
+	for i, word := range other.words() {
+		if i >= len(words) {
+			words = append(words, 0)
+		}
+		words[i] |= word
+	}
+	return fromWords(words)
+}
+
+// AndNot returns the ordinals in b but not in other.
+func (b *Bitmap) AndNot(other *Bitmap) *Bitmap {
+	if b.dense == nil {
+		var ordinals []uint32
+		for _, ordinal := range b.sparse {
+			if !other.Contains(int(ordinal)) {
+				ordinals = append(ordinals, ordinal)
+			}
+		}
+		return &Bitmap{sparse: ordinals, count: len(ordinals)}
+	}
+
+	words := slices.Clone(b.dense)
+	for i, word := range other.words() {
+		if i >= len(words) {
+			break
+		}
+		words[i] &^= word
+	}
+	return fromWords(words)
+}
+
+// words returns the bitmap as bit words, converting a sparse bitmap.
+func (b *Bitmap) words() []uint64 {
+	if b.dense != nil || b.count == 0 {
+		return b.dense
+	}
+	words := make([]uint64, b.sparse[len(b.sparse)-1]/64+1)
+	for _, ordinal := range b.sparse {
+		words[ordinal/64] |= 1 << (ordinal % 64)
+	}
+	return words
+}
@@ -0,0 +1,305 @@
+package data
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/oleiade/goagain/internal/domain"
+	"github.com/oleiade/goagain/internal/query"
+)
+
+// Bitmap index fields, besides the value filter attributes and the legality
+// statuses.
+const (
+	fieldRarity = "rarity"
+	fieldColor  = "color"
+)
+
+// legalityFields are the legality statuses indexed per format, named as the
+// query language fields.
+var legalityFields = []string{"legal", "banned", "suspended", "restricted"}
+
+// BitmapIndex maps the values of card attributes to bitmaps of the cards
+// having them, by ordinal in the indexed cards. Searches intersect the
+// bitmaps of every filter before looking at a single card. It covers class,
+// type, keyword, set, pitch, rarity, color and format legality.
+type BitmapIndex struct {
+	all    *Bitmap
+	fields map[string]map[string]*Bitmap // by field, then folded value
+
+	// Normalized card names by ordinal, for name filters
+	names []string
+}
+
+// NewBitmapIndex builds a bitmap index over the given cards.
+func NewBitmapIndex(cards []*domain.Card) *BitmapIndex {
+	ordinals := make(map[string]map[string][]uint32)
+	add := func(field, value string, ordinal uint32) {
+		if value == "" {
+			return
+		}
+		value = foldValue(field, value)
+
+		byValue := ordinals[field]
+		if byValue == nil {
+			byValue = make(map[string][]uint32)
+			ordinals[field] = byValue
+		}
+
+		// Printings repeat sets and rarities
+		list := byValue[value]
+		if n := len(list); n > 0 && list[n-1] == ordinal {
+			return
+		}
+		byValue[value] = append(list, ordinal)
+	}
+
+	x := &BitmapIndex{
+		fields: make(map[string]map[string]*Bitmap),
+		names:  make([]string, len(cards)),
+	}
+	all := make([]uint32, len(cards))
+
+	for i, card := range cards {
+		ordinal := uint32(i)
+		all[i] = ordinal
+		x.names[i] = normalizeName(card.Name)
+
+		for _, class := range card.Classes() {
+			add(string(AttributeClass), class, ordinal)
+		}
+		for _, cardType := range card.Types {
+			add(string(AttributeType), cardType, ordinal)
+		}
+		for _, keyword := range card.CardKeywords {
+			add(string(AttributeKeyword), keyword, ordinal)
+		}
+		for _, printing := range card.Printings {
+			add(string(AttributeSet), printing.SetID, ordinal)
+			add(fieldRarity, printing.Rarity, ordinal)
+		}
+		add(string(AttributePitch), card.Pitch, ordinal)
+		add(fieldColor, card.Color, ordinal)
+
+		for _, format := range domain.Formats {
+			legality := card.GetLegality(format.ID)
+			statuses := [...]bool{legality.Legal, legality.Banned, legality.Suspended, legality.Restricted}
+			for j, field := range legalityFields {
+				if statuses[j] {
+					add(field, string(format.ID), ordinal)
+				}
+			}
+		}
+	}
+
+	x.all = newBitmap(all)
+	for field, byValue := range ordinals {
+		x.fields[field] = make(map[string]*Bitmap, len(byValue))
+		for value, list := range byValue {
+			x.fields[field][value] = newBitmap(list)
+		}
+	}
+
+	return x
+}
+
+// foldValue returns the key a value is indexed under. Values compare ignoring
+// case, except format IDs, which the legality checks take as they are.
+func foldValue(field, value string) string {
+	for _, legality := range legalityFields {
+		if field == legality {
+			return value
+		}
+	}
+	return strings.ToLower(value)
+}
+
+// Len returns the number of indexed values.
+func (x *BitmapIndex) Len() int {
+	n := 0
+	for _, byValue := range x.fields {
+		n += len(byValue)
+	}
+	return n
+}
+
+// lookup returns the cards with a value of a field.
+func (x *BitmapIndex) lookup(field, value string) *Bitmap {
+	if b := x.fields[field][foldValue(field, value)]; b != nil {
+		return b
+	}
+	return &Bitmap{}
+}
+
+// lookupFunc returns the cards with any value of a field that match.
+func (x *BitmapIndex) lookupFunc(field string, match func(value string) bool) *Bitmap {
+	result := &Bitmap{}
+	for value, b := range x.fields[field] {
+		if match(value) {
+			result = result.Or(b)
+		}
+	}
+	return result
+}
+
+// attributeBitmap returns the cards with a value of a value filter attribute,
+// matching keywords partially as hasAttributeValue does.
+func (x *BitmapIndex) attributeBitmap(attribute CardAttribute, value string) *Bitmap {
+	if attribute == AttributeKeyword {
+		value = strings.ToLower(value)
+		return x.lookupFunc(string(attribute), func(keyword string) bool {
+			return strings.Contains(keyword, value)
+		})
+	}
+	return x.lookup(string(attribute), value)
+}
+
+// filterBitmap returns the cards passing a value filter.
+func (x *BitmapIndex) filterBitmap(f ValueFilter) *Bitmap {
+	if f.Mode == MatchAll && !f.Negate {
+		result := x.all
+		for _, value := range f.Values {
+			result = result.And(x.attributeBitmap(f.Attribute, value))
+		}
+		return result
+	}
+
+	union := &Bitmap{}
+	for _, value := range f.Values {
+		union = union.Or(x.attributeBitmap(f.Attribute, value))
+	}
+	if f.Negate {
+		return x.all.AndNot(union)
+	}
+	return union
+}
+
+// match returns the cards passing the filter's value filters and its format
+// legality as of today, which are then checked exactly. It returns nil when
+// the filter has neither.
+func (x *BitmapIndex) match(filter CardFilter) *Bitmap {
+	var result *Bitmap
+	for _, f := range filter.valueFilters() {
+		if len(f.Values) > 0 {
+			result = intersect(result, x.filterBitmap(f))
+		}
+	}
+	if filter.LegalIn != "" && filter.LegalAsOf.IsZero() {
+		result = intersect(result, x.lookup("legal", string(filter.LegalIn)))
+	}
+	return result
+}
+
+// intersect intersects two bitmaps, where nil stands for every card.
+func intersect(a, b *Bitmap) *Bitmap {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	default:
+		return a.And(b)
+	}
+}
+
+// matchQuery returns a superset of the cards matching a query, from the terms
+// the index covers, and whether it is exact. It returns nil when the index
+// cannot narrow the query.
+func (x *BitmapIndex) matchQuery(node query.Node, rarities map[string]*domain.Rarity) (*Bitmap, bool) {
+	switch n := node.(type) {
+	case *query.And:
+		var result *Bitmap
+		exact := true
+		for _, child := range n.Nodes {
+			b, childExact := x.matchQuery(child, rarities)
+			result = intersect(result, b)
+			exact = exact && childExact
+		}
+		return result, exact
+
+	case *query.Or:
+		result := &Bitmap{}
+		exact := true
+		for _, child := range n.Nodes {
+			b, childExact := x.matchQuery(child, rarities)
+			if b == nil {
+				return nil, false
+			}
+			result = result.Or(b)
+			exact = exact && childExact
+		}
+		return result, exact
+
+	case *query.Not:
+		// Only the complement of an exact match leaves out no matching card
+		if b, exact := x.matchQuery(n.Node, rarities); b != nil && exact {
+			return x.all.AndNot(b), true
+		}
+		return nil, false
+
+	case *query.Term:
+		return x.matchTerm(n, rarities)
+
+	default:
+		return nil, false
+	}
+}
+
+// matchTerm returns the cards matching a query term, as matchesTerm does, or
+// nil for terms the index does not cover.
+func (x *BitmapIndex) matchTerm(term *query.Term, rarities map[string]*domain.Rarity) (*Bitmap, bool) {
+	switch term.Field {
+	case "pitch":
+		return x.lookupFunc(string(AttributePitch), func(value string) bool {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			return err == nil && term.Op.Compare(n, term.Number)
+		}), true
+	case "legal", "banned", "suspended", "restricted":
+		return x.lookup(term.Field, term.Value), true
+	}
+
+	// Inequality holds for cards without the value, which the index does
+	// not list, and a blank value matches them too
+	if term.Op == query.OpNotEqual || term.Value == "" {
+		return nil, false
+	}
+
+	value := strings.ToLower(term.Value)
+	contains := func(v string) bool { return strings.Contains(v, value) }
+	switch term.Field {
+	case "class":
+		if term.Op == query.OpMatch {
+			return x.lookupFunc(string(AttributeClass), contains), true
+		}
+		return x.lookup(string(AttributeClass), value), true
+	case "type":
+		// A partial type match is against the type line, which is not indexed
+		if term.Op == query.OpMatch {
+			return nil, false
+		}
+		return x.lookup(string(AttributeType), value), true
+	case "keyword":
+		if term.Op == query.OpMatch {
+			return x.lookupFunc(string(AttributeKeyword), contains), true
+		}
+		return x.lookup(string(AttributeKeyword), value), true
+	case "color":
+		if term.Op == query.OpMatch {
+			return x.lookupFunc(fieldColor, contains), true
+		}
+		return x.lookup(fieldColor, value), true
+	case "set":
+		return x.lookup(string(AttributeSet), value), true
+	case "rarity":
+		// Rarities match by code or by name
+		result := x.lookup(fieldRarity, value)
+		for code, rarity := range rarities {
+			if strings.EqualFold(rarity.Name, value) {
+				result = result.Or(x.lookup(fieldRarity, code))
+			}
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
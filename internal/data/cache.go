@@ -0,0 +1,196 @@
+package data
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oleiade/goagain/internal/domain"
+	"github.com/oleiade/goagain/internal/observability"
+)
+
+// DefaultResultCacheSize is the number of card searches a provider's result
+// cache holds unless configured otherwise.
+const DefaultResultCacheSize = 1024
+
+// ResultCache is a bounded, least recently used cache of card search results,
+// shared by the stores a provider serves. Results are keyed by the normalized
+// filter and the data version, and only the store being served gets or puts
+// any, so that searches still running on a replaced store neither get its
+// successor's results nor keep it in memory through their own.
+type ResultCache struct {
+	size    int
+	metrics *observability.Metrics
+
+	mu      sync.Mutex
+	store   *Store     // store the results belong to
+	order   *list.List // most recently used first
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key     string
+	results []domain.ScoredCard
+}
+
+// NewResultCache returns a cache of at most size search results. Metrics may
+// be nil.
+func NewResultCache(size int, metrics *observability.Metrics) *ResultCache {
+	return &ResultCache{
+		size:    size,
+		metrics: metrics,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the results cached for a key, if the store is the one served.
+func (c *ResultCache) get(store *Store, key string) ([]domain.ScoredCard, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok || store != c.store {
+		if c.metrics != nil {
+			c.metrics.RecordCacheMiss(observability.CacheCardSearch)
+		}
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	if c.metrics != nil {
+		c.metrics.RecordCacheHit(observability.CacheCardSearch)
+	}
+	return element.Value.(*cacheEntry).results, true
+}
+
+// put caches a store's results for a key, evicting the least recently used
+// results when the cache is full. Results of a store no longer served are
+// dropped.
+func (c *ResultCache) put(store *Store, key string, results []domain.ScoredCard) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if store != c.store {
+		return
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = &cacheEntry{key: key, results: results}
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, results: results})
+
+	evicted := 0
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		evicted++
+	}
+	if evicted > 0 && c.metrics != nil {
+		c.metrics.RecordCacheEvictions(observability.CacheCardSearch, evicted, "capacity")
+	}
+}
+
+// use makes the cache hold the results of the given store, dropping those
+// of the store it replaces.
+func (c *ResultCache) use(store *Store) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if store == c.store {
+		return
+	}
+	c.store = store
+	if n := c.order.Len(); n > 0 && c.metrics != nil {
+		c.metrics.RecordCacheEvictions(observability.CacheCardSearch, n, "invalidated")
+	}
+	c.order.Init()
+	clear(c.entries)
+}
+
+// Len returns the number of cached results.
+func (c *ResultCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// cacheKey returns the filter normalized so that filters matching the same
+// cards in the same order share a key: case is folded where matching ignores
+// it, the single value fields join the value filters, values are sorted, the
+// order is left out for the default sort, which ignores it, and so is
+// pagination since results are cached whole.
+func (f CardFilter) cacheKey(version string) string {
+	type valueKey struct {
+		Attribute CardAttribute `json:"a"`
+		Values    []string      `json:"v"`
+		All       bool          `json:"all,omitempty"`
+		Negate    bool          `json:"not,omitempty"`
+	}
+	var values []valueKey
+	for _, v := range f.valueFilters() {
+		if len(v.Values) == 0 {
+			continue
+		}
+
+		key := valueKey{Attribute: v.Attribute, Negate: v.Negate}
+		for _, value := range v.Values {
+			key.Values = append(key.Values, strings.ToLower(value))
+		}
+		slices.Sort(key.Values)
+		key.Values = slices.Compact(key.Values)
+		// A negated filter excludes any of its values whatever the mode, and
+		// a single value matches the same way in either mode
+		key.All = v.Mode == MatchAll && !v.Negate && len(key.Values) > 1
+		values = append(values, key)
+	}
+	slices.SortFunc(values, func(a, b valueKey) int {
+		x, _ := json.Marshal(a)
+		y, _ := json.Marshal(b)
+		return bytes.Compare(x, y)
+	})
+
+	var queryKey, legalAsOf string
+	if f.Query != nil {
+		queryKey = f.Query.String()
+	}
+	if !f.LegalAsOf.IsZero() {
+		legalAsOf = f.LegalAsOf.UTC().Format(time.RFC3339)
+	}
+
+	raw, _ := json.Marshal(struct {
+		Version         string                        `json:"version"`
+		Name            string                        `json:"name,omitempty"`
+		Values          []valueKey                    `json:"values,omitempty"`
+		Text            string                        `json:"text,omitempty"`
+		LegalIn         domain.Format                 `json:"legal_in,omitempty"`
+		LegalAsOf       string                        `json:"legal_as_of,omitempty"`
+		Query           string                        `json:"query,omitempty"`
+		StatRanges      map[domain.StatName]StatRange `json:"stats,omitempty"`
+		IncludeVariable bool                          `json:"variable,omitempty"`
+		Sort            CardSort                      `json:"sort,omitempty"`
+		Descending      bool                          `json:"desc,omitempty"`
+		CollapseFaces   bool                          `json:"collapse,omitempty"`
+	}{
+		Version:         version,
+		Name:            normalizeName(f.Name),
+		Values:          values,
+		Text:            strings.ToLower(f.TextQuery),
+		LegalIn:         f.LegalIn,
+		LegalAsOf:       legalAsOf,
+		Query:           queryKey,
+		StatRanges:      f.StatRanges,
+		IncludeVariable: f.IncludeVariable,
+		Sort:            f.Sort,
+		Descending:      f.Sort != CardSortDefault && f.Sort.descending(f.Order),
+		CollapseFaces:   f.CollapseFaces,
+	})
+	return string(raw)
+}
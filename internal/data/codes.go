@@ -0,0 +1,109 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+// codeRecord is the shape shared by the code lookup tables. rarity.json names
+// its entries in a description field rather than a name field.
+type codeRecord struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (s *Store) readCodes(file string) ([]codeRecord, error) {
+	data, err := s.readFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", file, err)
+	}
+
+	var records []codeRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+
+	for i := range records {
+		if records[i].Name == "" {
+			records[i].Name = records[i].Description
+		}
+	}
+
+	return records, nil
+}
+
+// loadCodes loads the rarity, foiling, edition and art variation lookup tables.
+func (s *Store) loadCodes() error {
+	rarities, err := s.readCodes("rarity.json")
+	if err != nil {
+		return err
+	}
+	for _, r := range rarities {
+		rarity := &domain.Rarity{ID: r.ID, Name: r.Name}
+		s.Rarities = append(s.Rarities, rarity)
+		s.RaritiesByID[rarity.ID] = rarity
+	}
+
+	foilings, err := s.readCodes("foiling.json")
+	if err != nil {
+		return err
+	}
+	for _, r := range foilings {
+		foiling := &domain.Foiling{ID: r.ID, Name: r.Name}
+		s.Foilings = append(s.Foilings, foiling)
+		s.FoilingsByID[foiling.ID] = foiling
+	}
+
+	editions, err := s.readCodes("edition.json")
+	if err != nil {
+		return err
+	}
+	for _, r := range editions {
+		edition := &domain.Edition{ID: r.ID, Name: r.Name}
+		s.Editions = append(s.Editions, edition)
+		s.EditionsByID[edition.ID] = edition
+	}
+
+	variations, err := s.readCodes("art-variation.json")
+	if err != nil {
+		return err
+	}
+	for _, r := range variations {
+		variation := &domain.ArtVariation{ID: r.ID, Name: r.Name}
+		s.ArtVariations = append(s.ArtVariations, variation)
+		s.ArtVariationsByID[variation.ID] = variation
+	}
+
+	return nil
+}
+
+// DescribePrinting returns a copy of the printing with the human-readable
+// names of its set and codes filled in. Unknown codes are left out.
+func (s *Store) DescribePrinting(printing *domain.CardPrinting) *domain.CardPrinting {
+	names := &domain.PrintingNames{}
+
+	if set := s.SetsByID[printing.SetID]; set != nil {
+		names.Set = set.Name
+	}
+	if edition := s.EditionsByID[printing.Edition]; edition != nil {
+		names.Edition = edition.Name
+	}
+	if foiling := s.FoilingsByID[printing.Foiling]; foiling != nil {
+		names.Foiling = foiling.Name
+	}
+	if rarity := s.RaritiesByID[printing.Rarity]; rarity != nil {
+		names.Rarity = rarity.Name
+	}
+	for _, id := range printing.ArtVariations {
+		if variation := s.ArtVariationsByID[id]; variation != nil {
+			names.ArtVariations = append(names.ArtVariations, variation.Name)
+		}
+	}
+
+	described := *printing
+	described.Names = names
+	return &described
+}
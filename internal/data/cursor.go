@@ -0,0 +1,225 @@
+package data
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+// cursor is the decoded form of the opaque cursors handed out with pages. It
+// holds the key of the result the next page starts after, or the previous
+// page ends before, so that pages resume from a result rather than from a
+// position that shifts as results change.
+type cursor struct {
+	Version string    `json:"v"`
+	Sort    string    `json:"s"`
+	Filter  string    `json:"f"` // hash of the filter, see filterHash
+	Key     resultKey `json:"k"`
+	Before  bool      `json:"b,omitempty"`
+}
+
+func (c cursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// CursorError is returned for cursors that cannot be resumed from.
+type CursorError struct {
+	// Stale is set for cursors issued for another version of the data, whose
+	// results may have changed since
+	Stale   bool
+	Message string
+}
+
+func (e *CursorError) Error() string {
+	return e.Message
+}
+
+// filterHash identifies a search by its filter, without pagination, so that a
+// cursor is only resumed by the search it was issued for.
+func filterHash(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:8])
+}
+
+// decodeCursor decodes a cursor and checks that it was issued for this
+// version of the data, for the same sort and for the same filter.
+func (s *Store) decodeCursor(token, sort, filter string) (cursor, error) {
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(raw, &c) != nil {
+		return cursor{}, &CursorError{Message: "invalid cursor"}
+	}
+
+	if c.Version != s.Version {
+		return cursor{}, &CursorError{
+			Stale:   true,
+			Message: fmt.Sprintf("cursor is for data version %s, but the data is now at version %s; restart from the first page", c.Version, s.Version),
+		}
+	}
+	if c.Sort != sort {
+		return cursor{}, &CursorError{Message: "cursor was issued for another sort order; restart from the first page"}
+	}
+	if c.Filter != filter {
+		return cursor{}, &CursorError{Message: "cursor was issued for other filters; restart from the first page"}
+	}
+
+	return c, nil
+}
+
+// pager pages through sorted results of n items. key returns the key of the
+// item at an index and compare orders keys as the results are sorted.
+type pager struct {
+	store   *Store
+	sort    string
+	filter  string // see filterHash
+	n       int
+	key     func(i int) resultKey
+	compare func(a, b resultKey) int
+}
+
+// page returns the bounds of the page starting at the cursor, or at the
+// offset without one, and the cursors to the neighbouring pages. A zero limit
+// runs to the end of the results.
+func (p pager) page(token string, offset, limit int) (start, end int, next, prev string, err error) {
+	start, end = min(max(offset, 0), p.n), p.n
+
+	var before bool
+	if token != "" {
+		c, err := p.store.decodeCursor(token, p.sort, p.filter)
+		if err != nil {
+			return 0, 0, "", "", err
+		}
+
+		// The first result at or past the cursor key. The result the cursor
+		// was taken from may no longer match, so its key is searched for
+		// rather than the result itself.
+		at := sort.Search(p.n, func(i int) bool { return p.compare(p.key(i), c.Key) >= 0 })
+		if c.Before {
+			before = true
+			start, end = 0, at
+		} else {
+			start = at
+			if at < p.n && p.compare(p.key(at), c.Key) == 0 {
+				start++
+			}
+		}
+	}
+
+	if limit > 0 {
+		if before {
+			start = max(end-limit, 0)
+		} else {
+			end = min(start+limit, p.n)
+		}
+	}
+
+	if start < end {
+		if end < p.n {
+			next = cursor{Version: p.store.Version, Sort: p.sort, Filter: p.filter, Key: p.key(end - 1)}.encode()
+		}
+		if start > 0 {
+			prev = cursor{Version: p.store.Version, Sort: p.sort, Filter: p.filter, Key: p.key(start), Before: true}.encode()
+		}
+	}
+	return start, end, next, prev, nil
+}
+
+// CardPage is a page of card search results.
+type CardPage struct {
+	Cards  []domain.ScoredCard
+	Total  int
+	Offset int // Position of the first card in all the results
+	Facets FacetCounts
+
+	// Next and Prev are cursors to the following and preceding pages, empty
+	// when there is none
+	Next string
+	Prev string
+
+	// Cached is set when the results came from the result cache
+	Cached bool
+}
+
+// SearchCardsPage is SearchCardsFaceted with cursors. When the filter has a
+// cursor from a previous page, the page resumes from it and the offset is
+// ignored. A cursor fixes where the page starts, not what it costs: every
+// page runs the whole search, which the result cache saves on repeated pages.
+func (s *Store) SearchCardsPage(filter CardFilter, facets []Facet) (CardPage, error) {
+	results, cached := s.matchCards(filter)
+	descending := filter.Sort.descending(filter.Order)
+
+	p := pager{
+		store:  s,
+		sort:   fmt.Sprintf("cards:%s:%t", filter.Sort, descending),
+		filter: filterHash(filter.cacheKey("")),
+		n:      len(results),
+		key:    func(i int) resultKey { return s.cardKey(results[i], filter.Sort) },
+		compare: func(a, b resultKey) int {
+			return compareCardKeys(a, b, filter.Sort, descending)
+		},
+	}
+	start, end, next, prev, err := p.page(filter.Cursor, filter.Offset, filter.Limit)
+	if err != nil {
+		return CardPage{}, err
+	}
+
+	return CardPage{
+		Cards:  slices.Clone(results[start:end]),
+		Total:  len(results),
+		Offset: start,
+		Facets: countFacets(results, facets),
+		Next:   next,
+		Prev:   prev,
+		Cached: cached,
+	}, nil
+}
+
+// SetPage is a page of set search results.
+type SetPage struct {
+	Sets   []*domain.Set
+	Total  int
+	Offset int
+
+	// Next and Prev are cursors to the following and preceding pages, empty
+	// when there is none
+	Next string
+	Prev string
+}
+
+// SearchSetsPage is SearchSets with pagination by the filter's limit and
+// cursor. Without a limit the page holds every set from the cursor on.
+func (s *Store) SearchSetsPage(filter SetFilter) (SetPage, error) {
+	results := s.SearchSets(filter)
+	descending := filter.Order == OrderDescending
+
+	p := pager{
+		store:  s,
+		sort:   fmt.Sprintf("sets:%s:%t", filter.Sort, descending),
+		filter: filterHash(strings.Join([]string{filter.Name, filter.ID, filter.Query}, "\x00")),
+		n:      len(results),
+		key:    func(i int) resultKey { return s.setKey(results[i], filter.Sort) },
+		compare: func(a, b resultKey) int {
+			return compareSetKeys(a, b, filter.Sort, descending)
+		},
+	}
+	start, end, next, prev, err := p.page(filter.Cursor, 0, filter.Limit)
+	if err != nil {
+		return SetPage{}, err
+	}
+
+	return SetPage{
+		Sets:   results[start:end:end],
+		Total:  len(results),
+		Offset: start,
+		Next:   next,
+		Prev:   prev,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package data
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+// Diff compares two stores and returns the cards added, removed, and changed
+// in text, stats or current legality going from one to the other.
+func Diff(from, to *Store) *domain.Changelog {
+	changelog := &domain.Changelog{
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		GeneratedAt: time.Now().UTC(),
+		Added:       make([]domain.CardSummary, 0),
+		Removed:     make([]domain.CardSummary, 0),
+		Changed:     make([]domain.CardChange, 0),
+	}
+
+	for _, card := range to.Cards {
+		before := from.CardsByID[card.UniqueID]
+		if before == nil {
+			changelog.Added = append(changelog.Added, card.Summary())
+			continue
+		}
+
+		if changes := diffCard(before, card); len(changes) > 0 {
+			changelog.Changed = append(changelog.Changed, domain.CardChange{
+				CardSummary: card.Summary(),
+				Changes:     changes,
+			})
+
+			for _, change := range changes {
+				switch change.Kind {
+				case domain.ChangeText:
+					changelog.Summary.Text++
+				case domain.ChangeStats:
+					changelog.Summary.Stats++
+				case domain.ChangeLegality:
+					changelog.Summary.Legality++
+				}
+			}
+		}
+	}
+
+	for _, card := range from.Cards {
+		if to.CardsByID[card.UniqueID] == nil {
+			changelog.Removed = append(changelog.Removed, card.Summary())
+		}
+	}
+
+	bySummary := func(a, b domain.CardSummary) int {
+		return cmp.Or(
+			strings.Compare(a.Name, b.Name),
+			strings.Compare(a.Pitch, b.Pitch),
+			strings.Compare(a.UniqueID, b.UniqueID),
+		)
+	}
+	slices.SortFunc(changelog.Added, bySummary)
+	slices.SortFunc(changelog.Removed, bySummary)
+	slices.SortFunc(changelog.Changed, func(a, b domain.CardChange) int {
+		return bySummary(a.CardSummary, b.CardSummary)
+	})
+
+	changelog.Summary.Added = len(changelog.Added)
+	changelog.Summary.Removed = len(changelog.Removed)
+	changelog.Summary.Changed = len(changelog.Changed)
+
+	return changelog
+}
+
+// diffCard returns the field changes between two versions of a card.
+func diffCard(before, after *domain.Card) []domain.FieldChange {
+	var changes []domain.FieldChange
+	compare := func(field string, kind domain.ChangeKind, a, b string) {
+		if a != b {
+			changes = append(changes, domain.FieldChange{Field: field, Kind: kind, Before: a, After: b})
+		}
+	}
+
+	compare("name", domain.ChangeText, before.Name, after.Name)
+	compare("type_text", domain.ChangeText, before.TypeText, after.TypeText)
+	compare("functional_text", domain.ChangeText, before.FunctionalTextPlain, after.FunctionalTextPlain)
+
+	compare("pitch", domain.ChangeStats, before.Pitch, after.Pitch)
+	compare("cost", domain.ChangeStats, before.Cost, after.Cost)
+	compare("power", domain.ChangeStats, before.Power, after.Power)
+	compare("defense", domain.ChangeStats, before.Defense, after.Defense)
+	compare("health", domain.ChangeStats, before.Health, after.Health)
+	compare("intelligence", domain.ChangeStats, before.Intelligence, after.Intelligence)
+	compare("arcane", domain.ChangeStats, before.Arcane, after.Arcane)
+
+	for _, format := range domain.Formats {
+		compare("legality."+string(format.ID), domain.ChangeLegality,
+			before.GetLegality(format.ID).Status(), after.GetLegality(format.ID).Status())
+	}
+
+	return changes
+}
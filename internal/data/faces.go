@@ -0,0 +1,155 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+func (s *Store) loadFaceAssociations() error {
+	data, err := s.readFile("card-face-association.json")
+	if err != nil {
+		return fmt.Errorf("reading card-face-association.json: %w", err)
+	}
+
+	var associations []*domain.FaceAssociation
+	if err := json.Unmarshal(data, &associations); err != nil {
+		return fmt.Errorf("parsing card-face-association.json: %w", err)
+	}
+
+	s.FaceAssociations = associations
+	return nil
+}
+
+// indexFaces resolves face associations and double-sided printing info to
+// card-level links, and groups connected faces under a single logical card.
+func (s *Store) indexFaces() {
+	resolve := func(id string) *domain.Card {
+		if card := s.CardsByID[id]; card != nil {
+			return card
+		}
+		if printing := s.PrintingsByUniqueID[id]; printing != nil {
+			return printing.Card
+		}
+		return nil
+	}
+
+	seen := make(map[domain.FaceAssociation]bool)
+	link := func(front, back *domain.Card, isDFC bool) {
+		if front == nil || back == nil || front == back {
+			return
+		}
+		l := domain.FaceAssociation{FrontUniqueID: front.UniqueID, BackUniqueID: back.UniqueID, IsDFC: isDFC}
+		if seen[l] {
+			return
+		}
+		seen[l] = true
+		s.FaceLinksByCardID[front.UniqueID] = append(s.FaceLinksByCardID[front.UniqueID], l)
+		s.FaceLinksByCardID[back.UniqueID] = append(s.FaceLinksByCardID[back.UniqueID], l)
+	}
+
+	for _, assoc := range s.FaceAssociations {
+		link(resolve(assoc.FrontUniqueID), resolve(assoc.BackUniqueID), assoc.IsDFC)
+	}
+
+	for _, card := range s.Cards {
+		for _, printing := range card.Printings {
+			for _, info := range printing.DoubleSidedCardInfo {
+				other := resolve(info.OtherFaceUniqueID)
+				if info.IsFront {
+					link(card, other, info.IsDFC)
+				} else {
+					link(other, card, info.IsDFC)
+				}
+			}
+		}
+	}
+
+	// Group each chain under its first front face in card order, so search
+	// results can be collapsed to one logical card.
+	for _, card := range s.Cards {
+		if _, grouped := s.FaceGroupByCardID[card.UniqueID]; grouped {
+			continue
+		}
+		if len(s.FaceLinksByCardID[card.UniqueID]) == 0 {
+			continue
+		}
+
+		chain := s.walkFaces(card.UniqueID)
+		group := chain.Faces[0].UniqueID
+		for _, face := range chain.Faces {
+			if !s.isBackFace(face.UniqueID) {
+				group = face.UniqueID
+				break
+			}
+		}
+		for _, face := range chain.Faces {
+			s.FaceGroupByCardID[face.UniqueID] = group
+		}
+	}
+}
+
+// isBackFace reports whether the card is the back of any face association.
+func (s *Store) isBackFace(cardID string) bool {
+	return slices.ContainsFunc(s.FaceLinksByCardID[cardID], func(l domain.FaceAssociation) bool {
+		return l.BackUniqueID == cardID
+	})
+}
+
+// walkFaces collects every face reachable from a card through face links.
+func (s *Store) walkFaces(cardID string) *domain.CardFaces {
+	chain := &domain.CardFaces{
+		Root:  cardID,
+		Faces: []domain.CardSummary{},
+		Links: []domain.FaceAssociation{},
+	}
+
+	visited := map[string]bool{cardID: true}
+	seenLinks := make(map[domain.FaceAssociation]bool)
+	queue := []string{cardID}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		if card := s.CardsByID[id]; card != nil {
+			chain.Faces = append(chain.Faces, card.Summary())
+		}
+
+		for _, l := range s.FaceLinksByCardID[id] {
+			if !seenLinks[l] {
+				seenLinks[l] = true
+				chain.Links = append(chain.Links, l)
+			}
+			for _, next := range []string{l.FrontUniqueID, l.BackUniqueID} {
+				if !visited[next] {
+					visited[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	return chain
+}
+
+// GetCardFaces returns the chain of faces a card belongs to. A card without
+// other faces yields a chain holding only itself.
+func (s *Store) GetCardFaces(cardID string) *domain.CardFaces {
+	return s.walkFaces(cardID)
+}
+
+// GetOtherFaces returns the faces a card is directly associated with.
+func (s *Store) GetOtherFaces(cardID string) []*domain.Card {
+	var ids []string
+	for _, l := range s.FaceLinksByCardID[cardID] {
+		if l.FrontUniqueID == cardID {
+			ids = append(ids, l.BackUniqueID)
+		} else {
+			ids = append(ids, l.FrontUniqueID)
+		}
+	}
+	return s.resolveCards(ids)
+}
@@ -0,0 +1,123 @@
+package data
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+// Facet is a card attribute search results can be counted by.
+type Facet string
+
+const (
+	FacetClass   Facet = "class"
+	FacetType    Facet = "type"
+	FacetPitch   Facet = "pitch"
+	FacetKeyword Facet = "keyword"
+	FacetSet     Facet = "set"
+	FacetRarity  Facet = "rarity" // rarity codes of the card's printings
+)
+
+// Facets lists the facets.
+var Facets = []Facet{FacetClass, FacetType, FacetPitch, FacetKeyword, FacetSet, FacetRarity}
+
+// FacetCount is the number of matching cards with a facet value.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// FacetCounts holds the counts of each requested facet, most common values
+// first.
+type FacetCounts map[Facet][]FacetCount
+
+// ParseFacets parses a comma-separated list of facets, dropping duplicates.
+func ParseFacets(list string) ([]Facet, error) {
+	var facets []Facet
+	for _, value := range SplitValues(list) {
+		facet := Facet(strings.ToLower(value))
+		if !slices.Contains(Facets, facet) {
+			return nil, fmt.Errorf("invalid facet %q, expected one of %s", value, joinKeys(Facets))
+		}
+		if !slices.Contains(facets, facet) {
+			facets = append(facets, facet)
+		}
+	}
+	return facets, nil
+}
+
+// SearchCardsFaceted is SearchCardsScored with the counts of the requested
+// facets. Counts cover every match, not just the returned page, and a card
+// with several values of a facet, such as a card printed in two sets, counts
+// once under each.
+func (s *Store) SearchCardsFaceted(filter CardFilter, facets []Facet) ([]domain.ScoredCard, int, FacetCounts) {
+	results, _ := s.matchCards(filter)
+	return slices.Clone(paginate(results, filter.Offset, filter.Limit)), len(results), countFacets(results, facets)
+}
+
+// countFacets counts the cards per value of each facet.
+func countFacets(cards []domain.ScoredCard, facets []Facet) FacetCounts {
+	if len(facets) == 0 {
+		return nil
+	}
+
+	counts := make(FacetCounts, len(facets))
+	for _, facet := range facets {
+		byValue := make(map[string]int)
+		for _, card := range cards {
+			for _, value := range facetValues(card.Card, facet) {
+				byValue[value]++
+			}
+		}
+
+		values := make([]FacetCount, 0, len(byValue))
+		for value, count := range byValue {
+			values = append(values, FacetCount{Value: value, Count: count})
+		}
+		slices.SortFunc(values, func(a, b FacetCount) int {
+			if c := cmp.Compare(b.Count, a.Count); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.Value, b.Value)
+		})
+		counts[facet] = values
+	}
+	return counts
+}
+
+// facetValues returns the distinct values of a facet for a card.
+func facetValues(card *domain.Card, facet Facet) []string {
+	var values []string
+	switch facet {
+	case FacetClass:
+		values = card.Classes()
+	case FacetType:
+		values = card.Types
+	case FacetPitch:
+		if card.Pitch != "" {
+			values = []string{card.Pitch}
+		}
+	case FacetKeyword:
+		values = card.CardKeywords
+	case FacetSet:
+		for _, printing := range card.Printings {
+			values = append(values, printing.SetID)
+		}
+	case FacetRarity:
+		for _, printing := range card.Printings {
+			values = append(values, printing.Rarity)
+		}
+	}
+
+	// Printings repeat sets and rarities
+	var distinct []string
+	for _, value := range values {
+		if value != "" && !slices.Contains(distinct, value) {
+			distinct = append(distinct, value)
+		}
+	}
+	return distinct
+}
@@ -0,0 +1,47 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/oleiade/goagain/internal/domain"
+	"github.com/oleiade/goagain/internal/render"
+)
+
+func (s *Store) loadIcons() error {
+	data, err := s.readFile("icon.json")
+	if err != nil {
+		return fmt.Errorf("reading icon.json: %w", err)
+	}
+
+	var icons []*domain.Icon
+	if err := json.Unmarshal(data, &icons); err != nil {
+		return fmt.Errorf("parsing icon.json: %w", err)
+	}
+
+	s.Icons = icons
+	s.Renderer = render.NewRenderer(icons)
+	return nil
+}
+
+// RenderCard returns a copy of the card with its functional text rendered in
+// the given mode into FunctionalTextRendered. The card itself is returned
+// unchanged for render.ModeRaw.
+func (s *Store) RenderCard(card *domain.Card, mode render.Mode) *domain.Card {
+	if mode == render.ModeRaw {
+		return card
+	}
+
+	rendered := *card
+	rendered.FunctionalTextRendered = s.RenderText(card.FunctionalText, card.FunctionalTextPlain, mode)
+	return &rendered
+}
+
+// RenderText renders card text in the given mode, picking the Markdown source
+// for HTML and Markdown output and the plain source otherwise.
+func (s *Store) RenderText(markdown, plain string, mode render.Mode) string {
+	if mode == render.ModeHTML || mode == render.ModeMarkdown {
+		return s.Renderer.Render(markdown, mode)
+	}
+	return s.Renderer.Render(plain, mode)
+}
@@ -0,0 +1,193 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+// legalityFiles maps each dated legality source file to the format and status
+// its records describe.
+var legalityFiles = []struct {
+	file   string
+	format domain.Format
+	status domain.LegalityStatus
+}{
+	{"banned-blitz.json", domain.FormatBlitz, domain.StatusBanned},
+	{"banned-cc.json", domain.FormatCC, domain.StatusBanned},
+	{"banned-commoner.json", domain.FormatCommoner, domain.StatusBanned},
+	{"banned-ll.json", domain.FormatLL, domain.StatusBanned},
+	{"banned-silver-age.json", domain.FormatSilverAge, domain.StatusBanned},
+	{"banned-upf.json", domain.FormatUPF, domain.StatusBanned},
+	{"suspended-blitz.json", domain.FormatBlitz, domain.StatusSuspended},
+	{"suspended-cc.json", domain.FormatCC, domain.StatusSuspended},
+	{"suspended-commoner.json", domain.FormatCommoner, domain.StatusSuspended},
+	{"living-legend-blitz.json", domain.FormatBlitz, domain.StatusLivingLegend},
+	{"living-legend-cc.json", domain.FormatCC, domain.StatusLivingLegend},
+	{"restricted-ll.json", domain.FormatLL, domain.StatusRestricted},
+}
+
+func (s *Store) loadLegality() error {
+	for _, source := range legalityFiles {
+		data, err := s.readFile(source.file)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", source.file, err)
+		}
+
+		var events []*domain.LegalityEvent
+		if err := json.Unmarshal(data, &events); err != nil {
+			return fmt.Errorf("parsing %s: %w", source.file, err)
+		}
+
+		for _, event := range events {
+			event.Format = source.format
+			event.Status = source.status
+
+			history, ok := s.LegalityByCardID[event.CardUniqueID]
+			if !ok {
+				history = make(domain.LegalityHistory)
+				s.LegalityByCardID[event.CardUniqueID] = history
+			}
+			history.Add(event)
+		}
+
+		s.LegalityEvents = append(s.LegalityEvents, events...)
+	}
+
+	return nil
+}
+
+// GetLegalityHistory returns the dated legality events recorded for a card,
+// grouped by format. It returns nil if the card never had a status change.
+func (s *Store) GetLegalityHistory(cardID string) domain.LegalityHistory {
+	return s.LegalityByCardID[cardID]
+}
+
+// GetLegality returns a card's current legality in a format, with the
+// reasons it is not legal when it is not.
+func (s *Store) GetLegality(card *domain.Card, format domain.Format) domain.Legality {
+	legality := card.GetLegality(format)
+	legality.Explain(card, s.LegalityByCardID[card.UniqueID], time.Now())
+	return legality
+}
+
+// GetLegalityAt returns a card's legality in a format as it stood at the given
+// time, with the reasons it was not legal when it was not. Cards that had not
+// been released yet are never legal.
+func (s *Store) GetLegalityAt(card *domain.Card, format domain.Format, t time.Time) domain.Legality {
+	history := s.LegalityByCardID[card.UniqueID]
+	legality := card.GetLegalityAt(format, history, t)
+	legality.Explain(card, history, t)
+
+	if released, ok := s.ReleaseDateByCardID[card.UniqueID]; ok && t.Before(released) {
+		legality.Legal = false
+		legality.Reasons = append([]domain.LegalityReason{{Code: domain.ReasonNotReleased, End: &released}}, legality.Reasons...)
+	}
+
+	return legality
+}
+
+// GetBanList returns the cards banned, suspended, living legend or restricted
+// in a format at the given time, each ordered by card name.
+func (s *Store) GetBanList(format domain.FormatInfo, t time.Time) *domain.BanList {
+	banList := &domain.BanList{
+		FormatInfo:   format,
+		AsOf:         t.Format(time.DateOnly),
+		Banned:       []domain.BanListEntry{},
+		Suspended:    []domain.BanListEntry{},
+		LivingLegend: []domain.BanListEntry{},
+		Restricted:   []domain.BanListEntry{},
+	}
+
+	lists := map[domain.LegalityStatus]*[]domain.BanListEntry{
+		domain.StatusBanned:       &banList.Banned,
+		domain.StatusSuspended:    &banList.Suspended,
+		domain.StatusLivingLegend: &banList.LivingLegend,
+		domain.StatusRestricted:   &banList.Restricted,
+	}
+
+	for cardID, history := range s.LegalityByCardID {
+		for status, list := range lists {
+			event := history.ActiveAt(format.ID, status, t)
+			if event == nil {
+				continue
+			}
+
+			entry := domain.BanListEntry{
+				CardUniqueID:    cardID,
+				DateAnnounced:   event.DateAnnounced,
+				DateInEffect:    event.DateInEffect,
+				PlannedEnd:      event.PlannedEnd,
+				LegalityArticle: event.LegalityArticle,
+			}
+			if card := s.CardsByID[cardID]; card != nil {
+				entry.CardName = card.Name
+				entry.Pitch = card.Pitch
+			}
+			*list = append(*list, entry)
+		}
+	}
+
+	for _, list := range lists {
+		slices.SortFunc(*list, func(a, b domain.BanListEntry) int {
+			if c := strings.Compare(a.CardName, b.CardName); c != 0 {
+				return c
+			}
+			return strings.Compare(a.CardUniqueID, b.CardUniqueID)
+		})
+	}
+
+	return banList
+}
+
+// LegalityChangeFilter defines the window and format for legality change listings.
+type LegalityChangeFilter struct {
+	Since  time.Time     // Changes announced or in effect on or after this time
+	Until  time.Time     // Changes announced or in effect on or before this time (zero means no bound)
+	Format domain.Format // Restrict to one format (empty means all formats)
+}
+
+// GetLegalityChanges returns the legality events announced or taking effect in
+// the filter's window, ordered by effective date. Announced changes whose
+// effective date is still in the future are flagged as scheduled.
+func (s *Store) GetLegalityChanges(filter LegalityChangeFilter) []*domain.LegalityChange {
+	inWindow := func(t time.Time) bool {
+		if t.Before(filter.Since) {
+			return false
+		}
+		return filter.Until.IsZero() || !t.After(filter.Until)
+	}
+
+	now := time.Now()
+	var changes []*domain.LegalityChange
+	for _, event := range s.LegalityEvents {
+		if filter.Format != "" && event.Format != filter.Format {
+			continue
+		}
+		if !inWindow(event.DateAnnounced) && !inWindow(event.DateInEffect) {
+			continue
+		}
+
+		change := &domain.LegalityChange{
+			LegalityEvent: event,
+			Scheduled:     event.DateInEffect.After(now),
+		}
+		if card := s.CardsByID[event.CardUniqueID]; card != nil {
+			change.CardName = card.Name
+		}
+		changes = append(changes, change)
+	}
+
+	slices.SortStableFunc(changes, func(a, b *domain.LegalityChange) int {
+		if c := a.DateInEffect.Compare(b.DateInEffect); c != 0 {
+			return c
+		}
+		return strings.Compare(a.CardName, b.CardName)
+	})
+
+	return changes
+}
@@ -0,0 +1,335 @@
+package data
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/oleiade/goagain/internal/domain"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NameIndex looks card names up by prefix and tolerating typos. Names are
+// compared in normalized form: lower-cased, without diacritics, apostrophes or
+// other punctuation.
+type NameIndex struct {
+	entries []nameEntry      // sorted by key
+	byKey   map[string]int   // entry index by key
+	words   []nameWordSuffix // sorted by suffix
+}
+
+type nameEntry struct {
+	key   string
+	name  string
+	cards []*domain.Card
+}
+
+// nameWordSuffix is the tail of a name from one of its words onward, so that
+// prefixes match words after the first.
+type nameWordSuffix struct {
+	suffix string
+	entry  int
+}
+
+// NameMatch is a card name close to a looked up name.
+type NameMatch struct {
+	Name     string
+	Distance int // edits between the normalized names
+
+	cards []*domain.Card
+}
+
+// NewNameIndex builds a name index over the given cards.
+func NewNameIndex(cards []*domain.Card) *NameIndex {
+	cardsByKey := make(map[string][]*domain.Card)
+	for _, card := range cards {
+		key := normalizeName(card.Name)
+		if key != "" {
+			cardsByKey[key] = append(cardsByKey[key], card)
+		}
+	}
+
+	ix := &NameIndex{
+		entries: make([]nameEntry, 0, len(cardsByKey)),
+		byKey:   make(map[string]int, len(cardsByKey)),
+	}
+	for key, cards := range cardsByKey {
+		ix.entries = append(ix.entries, nameEntry{key: key, name: cards[0].Name, cards: cards})
+	}
+	slices.SortFunc(ix.entries, func(a, b nameEntry) int { return strings.Compare(a.key, b.key) })
+
+	for i, entry := range ix.entries {
+		ix.byKey[entry.key] = i
+		for pos := range len(entry.key) {
+			if entry.key[pos] == ' ' {
+				ix.words = append(ix.words, nameWordSuffix{suffix: entry.key[pos+1:], entry: i})
+			}
+		}
+	}
+	slices.SortFunc(ix.words, func(a, b nameWordSuffix) int { return strings.Compare(a.suffix, b.suffix) })
+
+	return ix
+}
+
+// Lookup returns the cards whose normalized name equals the given one's.
+func (ix *NameIndex) Lookup(name string) []*domain.Card {
+	if i, ok := ix.byKey[normalizeName(name)]; ok {
+		return ix.entries[i].cards
+	}
+	return nil
+}
+
+// Autocomplete returns up to limit names starting with the prefix, followed
+// by names with a later word starting with it.
+func (ix *NameIndex) Autocomplete(prefix string, limit int) []string {
+	prefix = normalizeName(prefix)
+	if prefix == "" || limit <= 0 {
+		return nil
+	}
+
+	var names []string
+	seen := make(map[int]bool)
+
+	i := sort.Search(len(ix.entries), func(i int) bool { return ix.entries[i].key >= prefix })
+	for ; i < len(ix.entries) && len(names) < limit; i++ {
+		if !strings.HasPrefix(ix.entries[i].key, prefix) {
+			break
+		}
+		seen[i] = true
+		names = append(names, ix.entries[i].name)
+	}
+
+	j := sort.Search(len(ix.words), func(j int) bool { return ix.words[j].suffix >= prefix })
+	for ; j < len(ix.words) && len(names) < limit; j++ {
+		word := ix.words[j]
+		if !strings.HasPrefix(word.suffix, prefix) {
+			break
+		}
+		if !seen[word.entry] {
+			seen[word.entry] = true
+			names = append(names, ix.entries[word.entry].name)
+		}
+	}
+
+	return names
+}
+
+// Suggest returns up to limit names within a typo tolerance of the given
+// name, closest first.
+func (ix *NameIndex) Suggest(name string, limit int) []NameMatch {
+	query := []rune(normalizeName(name))
+	if len(query) == 0 || limit <= 0 {
+		return nil
+	}
+
+	// Allow about one typo every four characters
+	maxDistance := max(1, len(query)/4)
+
+	var matches []NameMatch
+	for _, entry := range ix.entries {
+		key := []rune(entry.key)
+		if abs(len(key)-len(query)) > maxDistance {
+			continue
+		}
+		if distance := editDistance(query, key, maxDistance); distance <= maxDistance {
+			matches = append(matches, NameMatch{Name: entry.name, Distance: distance, cards: entry.cards})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b NameMatch) int { return a.Distance - b.Distance })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// normalizeName folds a name for comparison: "Oldhim's Ötzi-Spear" becomes
+// "oldhims otzi spear".
+func normalizeName(name string) string {
+	var b strings.Builder
+	gap := false
+
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Diacritics split off by the decomposition
+		case r == '\'' || r == '’':
+			// Possessives join their word
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if gap && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			gap = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			gap = true
+		}
+	}
+
+	return b.String()
+}
+
+// editDistance returns the optimal string alignment distance between a and b:
+// the insertions, deletions, substitutions and transpositions of adjacent
+// characters turning one into the other. It gives up past maxDistance,
+// returning maxDistance+1.
+func editDistance(a, b []rune, maxDistance int) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+
+		if rowMin > maxDistance {
+			return maxDistance + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return min(prev[len(b)], maxDistance+1)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// AutocompleteCardNames returns up to limit card names starting with the
+// prefix, or with a word starting with it.
+func (s *Store) AutocompleteCardNames(prefix string, limit int) []string {
+	if s.Names == nil {
+		return nil
+	}
+	return s.Names.Autocomplete(prefix, limit)
+}
+
+// FindCardsByName looks cards up by name, falling back to a normalized and
+// then a typo-tolerant match. It reports whether the match was inexact. A
+// misspelling equally close to several names matches none of them.
+func (s *Store) FindCardsByName(name string) ([]*domain.Card, bool) {
+	if cards := s.GetCardsByName(name); len(cards) > 0 {
+		return cards, false
+	}
+	if s.Names == nil {
+		return nil, false
+	}
+
+	if cards := s.Names.Lookup(name); len(cards) > 0 {
+		return cards, true
+	}
+
+	matches := s.Names.Suggest(name, 2)
+	if len(matches) == 0 || (len(matches) == 2 && matches[0].Distance == matches[1].Distance) {
+		return nil, false
+	}
+	return matches[0].cards, true
+}
+
+// SuggestCardNames returns up to limit card names close to the given one,
+// for "did you mean" hints.
+func (s *Store) SuggestCardNames(name string, limit int) []string {
+	if s.Names == nil {
+		return nil
+	}
+
+	var names []string
+	for _, match := range s.Names.Suggest(name, limit) {
+		names = append(names, match.Name)
+	}
+	if len(names) < limit {
+		// Fill in with names the input is the start of
+		for _, candidate := range s.Names.Autocomplete(name, limit) {
+			if len(names) == limit {
+				break
+			}
+			if !slices.Contains(names, candidate) {
+				names = append(names, candidate)
+			}
+		}
+	}
+	return names
+}
+
+// CardMatch is the result of resolving a card reference with ResolveCard.
+type CardMatch struct {
+	// Card is the single card resolved, nil when there is none or several
+	Card *domain.Card
+
+	// Candidates are the cards sharing the name when more than one remains
+	// after the pitch and color selectors, such as the pitch variants of an
+	// attack
+	Candidates []*domain.Card
+
+	// Fuzzy is set when the reference only matched a card name after
+	// normalization or typo correction
+	Fuzzy bool
+}
+
+// ResolveCard resolves a unique ID, printing ID or name to a single card.
+// Names shared by several cards, usually pitch variants, are narrowed by the
+// pitch and color selectors (case-insensitive, empty matches any); when more
+// than one card remains, they are returned as candidates.
+func (s *Store) ResolveCard(id, pitch, color string) CardMatch {
+	if card := s.GetCardByID(id); card != nil {
+		return CardMatch{Card: card}
+	}
+	if card := s.GetCardByPrintingID(id); card != nil {
+		return CardMatch{Card: card}
+	}
+
+	cards, fuzzy := s.FindCardsByName(id)
+
+	var selected []*domain.Card
+	for _, card := range cards {
+		if pitch != "" && card.Pitch != pitch {
+			continue
+		}
+		if color != "" && !strings.EqualFold(card.Color, color) {
+			continue
+		}
+		selected = append(selected, card)
+	}
+
+	switch {
+	case len(selected) == 0:
+		return CardMatch{}
+	case len(selected) == 1 || s.sameFaceGroup(selected):
+		return CardMatch{Card: selected[0], Fuzzy: fuzzy}
+	default:
+		return CardMatch{Candidates: selected, Fuzzy: fuzzy}
+	}
+}
+
+// sameFaceGroup reports whether the cards are all faces of one logical card.
+func (s *Store) sameFaceGroup(cards []*domain.Card) bool {
+	group, ok := s.FaceGroupByCardID[cards[0].UniqueID]
+	if !ok {
+		return false
+	}
+	for _, card := range cards[1:] {
+		if s.FaceGroupByCardID[card.UniqueID] != group {
+			return false
+		}
+	}
+	return true
+}
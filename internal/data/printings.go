@@ -0,0 +1,111 @@
+package data
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+// indexPrintings builds the printing indexes from the loaded cards.
+func (s *Store) indexPrintings() {
+	for _, card := range s.Cards {
+		for i := range card.Printings {
+			printing := &domain.CardPrinting{Printing: &card.Printings[i], Card: card}
+			s.Printings = append(s.Printings, printing)
+			s.PrintingsByUniqueID[printing.UniqueID] = printing
+
+			id := strings.ToUpper(printing.ID)
+			s.PrintingsByID[id] = append(s.PrintingsByID[id], printing)
+		}
+	}
+}
+
+// GetPrintings returns the printings matching a printing unique ID or a
+// collector ID such as "WTR001" (case-insensitive). A collector ID usually
+// matches several printings, one per edition and foiling.
+func (s *Store) GetPrintings(id string) []*domain.CardPrinting {
+	if printing := s.PrintingsByUniqueID[id]; printing != nil {
+		return []*domain.CardPrinting{printing}
+	}
+	return s.PrintingsByID[strings.ToUpper(id)]
+}
+
+// GetCardByPrintingID returns the card a printing unique ID or collector ID
+// belongs to.
+func (s *Store) GetCardByPrintingID(id string) *domain.Card {
+	if printings := s.GetPrintings(id); len(printings) > 0 {
+		return printings[0].Card
+	}
+	return nil
+}
+
+// PrintingFilter defines filtering criteria for printing searches. Codes are
+// matched case-insensitively.
+type PrintingFilter struct {
+	SetID        string
+	Rarity       string
+	Foiling      string
+	Edition      string
+	ArtVariation string
+	Limit        int
+	Offset       int
+}
+
+// SearchPrintings searches for printings matching the given filter criteria.
+// It returns the paginated results and the total number of matches.
+func (s *Store) SearchPrintings(filter PrintingFilter) ([]*domain.CardPrinting, int) {
+	var results []*domain.CardPrinting
+
+	for _, printing := range s.Printings {
+		if !matchesPrintingFilter(printing, filter) {
+			continue
+		}
+		results = append(results, printing)
+	}
+
+	total := len(results)
+
+	// Apply pagination
+	if filter.Offset > 0 {
+		if filter.Offset >= len(results) {
+			return nil, total // Page is out of bounds
+		}
+		results = results[filter.Offset:]
+	}
+
+	if filter.Limit > 0 && len(results) > filter.Limit {
+		results = results[:filter.Limit]
+	}
+
+	return results, total
+}
+
+func matchesPrintingFilter(printing *domain.CardPrinting, filter PrintingFilter) bool {
+	if filter.SetID != "" && !strings.EqualFold(printing.SetID, filter.SetID) {
+		return false
+	}
+
+	if filter.Rarity != "" && !strings.EqualFold(printing.Rarity, filter.Rarity) {
+		return false
+	}
+
+	if filter.Foiling != "" && !strings.EqualFold(printing.Foiling, filter.Foiling) {
+		return false
+	}
+
+	if filter.Edition != "" && !strings.EqualFold(printing.Edition, filter.Edition) {
+		return false
+	}
+
+	if filter.ArtVariation != "" {
+		hasVariation := slices.ContainsFunc(printing.ArtVariations, func(v string) bool {
+			return strings.EqualFold(v, filter.ArtVariation)
+		})
+		if !hasVariation {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,240 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/oleiade/goagain/internal/domain"
+	"github.com/oleiade/goagain/internal/observability"
+)
+
+// ProviderConfig configures where a Provider loads data from.
+type ProviderConfig struct {
+	// Dir is a directory to load the JSON files from instead of the embedded data
+	Dir string
+
+	// Strict refuses to serve data that fails validation
+	Strict bool
+
+	// ChangelogFile keeps the changelog of the last reload that changed the
+	// data, so it survives restarts. Without it the changelog is kept in
+	// memory only.
+	ChangelogFile string
+
+	// CacheSize is the number of card searches whose results are cached, or
+	// zero to cache none
+	CacheSize int
+}
+
+// LoadProviderConfig loads the provider configuration from environment variables.
+func LoadProviderConfig() ProviderConfig {
+	config := ProviderConfig{
+		Dir:           os.Getenv("DATA_DIR"),
+		ChangelogFile: os.Getenv("CHANGELOG_FILE"),
+		CacheSize:     DefaultResultCacheSize,
+	}
+
+	if size, err := strconv.Atoi(os.Getenv("RESULT_CACHE_SIZE")); err == nil && size >= 0 {
+		config.CacheSize = size
+	}
+	if strict, err := strconv.ParseBool(os.Getenv("STRICT_DATA")); err == nil {
+		config.Strict = strict
+	}
+
+	return config
+}
+
+// Provider holds the store being served and replaces it on reload. A new
+// store is built completely before being swapped in, so callers holding the
+// previous store keep a consistent view until they ask for the current one.
+type Provider struct {
+	current atomic.Pointer[Store]
+	config  ProviderConfig
+
+	// changelog is the diff from the previously served data version to the
+	// current one, nil until a reload changes the data
+	changelog atomic.Pointer[domain.Changelog]
+
+	metrics *observability.Metrics
+
+	// cache holds search results of the current store, nil when disabled
+	cache *ResultCache
+
+	// reloadMu serializes reloads so two of them never build stores at once.
+	reloadMu sync.Mutex
+}
+
+// NewProvider loads the initial store, from the configured directory when it is
+// set or from the embedded data otherwise.
+func NewProvider(config ProviderConfig, metrics *observability.Metrics) (*Provider, error) {
+	p := &Provider{config: config, metrics: metrics}
+	if config.CacheSize > 0 {
+		p.cache = NewResultCache(config.CacheSize, metrics)
+	}
+
+	store, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	p.current.Store(store)
+	p.publishStats(store)
+	if p.cache != nil {
+		p.cache.use(store)
+	}
+
+	changelog, err := p.readChangelog()
+	if err != nil {
+		return nil, err
+	}
+	// A changelog recorded for other data, such as before a deploy, no
+	// longer describes the data being served
+	if changelog != nil && changelog.ToVersion == store.Version {
+		p.changelog.Store(changelog)
+	}
+
+	return p, nil
+}
+
+// Store returns the store currently being served.
+func (p *Provider) Store() *Store {
+	return p.current.Load()
+}
+
+// Source describes where the provider loads its data from.
+func (p *Provider) Source() string {
+	if p.config.Dir == "" {
+		return "embedded"
+	}
+	return p.config.Dir
+}
+
+// Reload builds a new store from the provider's source and swaps it in. On
+// error, including validation issues in strict mode, the current store is kept.
+func (p *Provider) Reload() (*Store, error) {
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+
+	store, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+
+	// Record what changed, unless the reload found the same data. The
+	// changelog is written first, so that a failure keeps the current store.
+	if previous := p.current.Load(); previous.Version != store.Version {
+		changelog := Diff(previous, store)
+		if err := p.writeChangelog(changelog); err != nil {
+			return nil, err
+		}
+		p.changelog.Store(changelog)
+	}
+	p.current.Store(store)
+	p.publishStats(store)
+
+	// Results of the previous store are never served again, so free them
+	if p.cache != nil {
+		p.cache.use(store)
+	}
+
+	return store, nil
+}
+
+// Changelog returns the changes between the previously served data version
+// and the current one, or nil if none was recorded: the data has not changed
+// since startup, and no changelog file holds one for the current version.
+func (p *Provider) Changelog() *domain.Changelog {
+	return p.changelog.Load()
+}
+
+func (p *Provider) load() (*Store, error) {
+	var store *Store
+	var err error
+	if p.config.Dir == "" {
+		store, err = NewStore(nil)
+	} else {
+		store, err = NewStoreFromDir(p.config.Dir, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading data from %s: %w", p.Source(), err)
+	}
+
+	if p.config.Strict {
+		if issues := store.Validate(); len(issues) > 0 {
+			return nil, &ValidationError{Issues: issues}
+		}
+	}
+	store.cache = p.cache
+
+	return store, nil
+}
+
+// publishStats sets the data metrics from the store being served. Stores are
+// loaded without metrics, so one refused under strict validation never
+// reports its numbers.
+func (p *Provider) publishStats(store *Store) {
+	if p.metrics == nil {
+		return
+	}
+	stats, indexStats := store.Stats()
+	p.metrics.SetDataStats(stats)
+	p.metrics.SetIndexStats(indexStats)
+}
+
+// readChangelog reads the changelog file, if configured and present.
+func (p *Provider) readChangelog() (*domain.Changelog, error) {
+	if p.config.ChangelogFile == "" {
+		return nil, nil
+	}
+
+	raw, err := os.ReadFile(p.config.ChangelogFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading changelog: %w", err)
+	}
+
+	var changelog domain.Changelog
+	if err := json.Unmarshal(raw, &changelog); err != nil {
+		return nil, fmt.Errorf("parsing changelog %s: %w", p.config.ChangelogFile, err)
+	}
+	return &changelog, nil
+}
+
+// writeChangelog replaces the changelog file, if configured. It writes to a
+// temporary file first, so a crash never leaves a partial changelog.
+func (p *Provider) writeChangelog(changelog *domain.Changelog) error {
+	if p.config.ChangelogFile == "" {
+		return nil
+	}
+
+	raw, err := json.Marshal(changelog)
+	if err != nil {
+		return fmt.Errorf("encoding changelog: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p.config.ChangelogFile), ".changelog-*")
+	if err != nil {
+		return fmt.Errorf("writing changelog: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing changelog: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing changelog: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p.config.ChangelogFile); err != nil {
+		return fmt.Errorf("writing changelog: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package data
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/oleiade/goagain/internal/domain"
+	"github.com/oleiade/goagain/internal/query"
+)
+
+// matchesQuery reports whether a card matches a parsed query.
+func (s *Store) matchesQuery(card *domain.Card, node query.Node) bool {
+	switch n := node.(type) {
+	case *query.And:
+		for _, child := range n.Nodes {
+			if !s.matchesQuery(card, child) {
+				return false
+			}
+		}
+		return true
+
+	case *query.Or:
+		for _, child := range n.Nodes {
+			if s.matchesQuery(card, child) {
+				return true
+			}
+		}
+		return false
+
+	case *query.Not:
+		return !s.matchesQuery(card, n.Node)
+
+	case *query.Term:
+		return s.matchesTerm(card, n)
+
+	default:
+		return false
+	}
+}
+
+func (s *Store) matchesTerm(card *domain.Card, term *query.Term) bool {
+	switch term.Field {
+	case "name":
+		return matchText(term, card.Name)
+	case "class":
+		// Any class, as with class filters: Ninja Warrior cards are both
+		return matchAnyText(term, card.Classes())
+	case "type":
+		// An exact match is against a single type, a partial one against the type line
+		if term.Op == query.OpMatch {
+			return matchText(term, card.TypeText)
+		}
+		return matchAnyText(term, card.Types)
+	case "keyword":
+		return matchAnyText(term, card.CardKeywords)
+	case "text":
+		return matchText(term, card.FunctionalTextPlain)
+	case "color":
+		return matchText(term, card.Color)
+	case "set":
+		return matchAnyPrinting(card, term, true, func(p domain.Printing) []string { return []string{p.SetID} })
+	case "artist":
+		return matchAnyPrinting(card, term, false, func(p domain.Printing) []string { return p.Artists })
+	case "rarity":
+		return matchAnyPrinting(card, term, true, func(p domain.Printing) []string {
+			names := []string{p.Rarity}
+			if rarity := s.RaritiesByID[p.Rarity]; rarity != nil {
+				names = append(names, rarity.Name)
+			}
+			return names
+		})
+
+	case "pitch", "cost", "power", "defense", "health", "intelligence", "arcane":
+		return matchStat(term, card.Stats.Get(domain.StatName(term.Field)))
+
+	case "legal":
+		return card.GetLegality(domain.Format(term.Value)).Legal
+	case "banned":
+		return card.GetLegality(domain.Format(term.Value)).Banned
+	case "suspended":
+		return card.GetLegality(domain.Format(term.Value)).Suspended
+	case "restricted":
+		return card.GetLegality(domain.Format(term.Value)).Restricted
+
+	default:
+		return false
+	}
+}
+
+// matchText matches a text field: ":" is a partial match, "=" and "!=" are
+// exact, all ignoring case.
+func matchText(term *query.Term, value string) bool {
+	switch term.Op {
+	case query.OpMatch:
+		return strings.Contains(strings.ToLower(value), strings.ToLower(term.Value))
+	case query.OpNotEqual:
+		return !strings.EqualFold(value, term.Value)
+	default:
+		return strings.EqualFold(value, term.Value)
+	}
+}
+
+// matchAnyText matches a multi-valued text field. "!=" holds when no value
+// equals the term's.
+func matchAnyText(term *query.Term, values []string) bool {
+	if term.Op == query.OpNotEqual {
+		return !slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, term.Value) })
+	}
+	return slices.ContainsFunc(values, func(v string) bool { return matchText(term, v) })
+}
+
+// matchAnyPrinting matches the values of a card's printings. Codes are exact,
+// so ":" matches them like "=".
+func matchAnyPrinting(card *domain.Card, term *query.Term, code bool, values func(domain.Printing) []string) bool {
+	if code && term.Op == query.OpMatch {
+		exact := *term
+		exact.Op = query.OpEqual
+		term = &exact
+	}
+
+	var all []string
+	for _, printing := range card.Printings {
+		all = append(all, values(printing)...)
+	}
+	return matchAnyText(term, all)
+}
+
+// matchStat compares a numeric stat. Variable stats such as "X" or "*" never
+// match.
+func matchStat(term *query.Term, stat *domain.Stat) bool {
+	if stat == nil || stat.Variable {
+		return false
+	}
+	return term.Op.Compare(stat.Value, term.Number)
+}
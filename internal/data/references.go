@@ -0,0 +1,113 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+func (s *Store) loadCardReferences() error {
+	data, err := s.readFile("card-reference.json")
+	if err != nil {
+		return fmt.Errorf("reading card-reference.json: %w", err)
+	}
+
+	var references []*domain.CardReference
+	if err := json.Unmarshal(data, &references); err != nil {
+		return fmt.Errorf("parsing card-reference.json: %w", err)
+	}
+
+	s.CardReferences = references
+
+	for _, ref := range references {
+		s.ReferencesByCardID[ref.CardUniqueID] = append(s.ReferencesByCardID[ref.CardUniqueID], ref.ReferencedCardUniqueID)
+		s.ReferencedByCardID[ref.ReferencedCardUniqueID] = append(s.ReferencedByCardID[ref.ReferencedCardUniqueID], ref.CardUniqueID)
+	}
+
+	return nil
+}
+
+// GetReferencedCards returns the cards a card refers to, such as the tokens
+// and auras it creates or the cards it names.
+func (s *Store) GetReferencedCards(cardID string) []*domain.Card {
+	return s.resolveCards(s.ReferencesByCardID[cardID])
+}
+
+// GetReferencingCards returns the cards that refer to a card.
+func (s *Store) GetReferencingCards(cardID string) []*domain.Card {
+	return s.resolveCards(s.ReferencedByCardID[cardID])
+}
+
+// resolveCards looks up cards by unique ID, skipping unknown and duplicate IDs.
+func (s *Store) resolveCards(ids []string) []*domain.Card {
+	seen := make(map[string]bool)
+	var results []*domain.Card
+	for _, id := range ids {
+		card := s.CardsByID[id]
+		if card == nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		results = append(results, card)
+	}
+	return results
+}
+
+// GetCardGraph walks the reference graph breadth-first from a card, following
+// edges in the given direction up to maxDepth hops.
+func (s *Store) GetCardGraph(root *domain.Card, maxDepth int, direction domain.GraphDirection) *domain.CardGraph {
+	graph := &domain.CardGraph{
+		Root:      root.UniqueID,
+		Depth:     maxDepth,
+		Direction: direction,
+		Nodes:     []domain.CardGraphNode{{CardSummary: root.Summary()}},
+		Edges:     []domain.CardReference{},
+	}
+
+	visited := map[string]bool{root.UniqueID: true}
+	seenEdges := make(map[domain.CardReference]bool)
+	frontier := []string{root.UniqueID}
+
+	addEdge := func(edge domain.CardReference) {
+		if !seenEdges[edge] {
+			seenEdges[edge] = true
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+
+	for depth := 1; depth <= maxDepth && len(frontier) > 0; depth++ {
+		var next []string
+
+		visit := func(id string, edge domain.CardReference) {
+			card := s.CardsByID[id]
+			if card == nil {
+				return
+			}
+			addEdge(edge)
+			if visited[id] {
+				return
+			}
+			visited[id] = true
+			graph.Nodes = append(graph.Nodes, domain.CardGraphNode{CardSummary: card.Summary(), Depth: depth})
+			next = append(next, id)
+		}
+
+		for _, id := range frontier {
+			if direction != domain.GraphReferencedBy {
+				for _, ref := range s.ReferencesByCardID[id] {
+					visit(ref, domain.CardReference{CardUniqueID: id, ReferencedCardUniqueID: ref})
+				}
+			}
+			if direction != domain.GraphReferences {
+				for _, ref := range s.ReferencedByCardID[id] {
+					visit(ref, domain.CardReference{CardUniqueID: ref, ReferencedCardUniqueID: id})
+				}
+			}
+		}
+
+		frontier = next
+	}
+
+	return graph
+}
@@ -0,0 +1,296 @@
+package data
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+// CardSort is the key card search results are sorted by.
+type CardSort string
+
+const (
+	// CardSortDefault ranks text queries by relevance and leaves other
+	// results in file order.
+	CardSortDefault     CardSort = ""
+	CardSortName        CardSort = "name"
+	CardSortCost        CardSort = "cost"
+	CardSortPower       CardSort = "power"
+	CardSortDefense     CardSort = "defense"
+	CardSortPitch       CardSort = "pitch"
+	CardSortReleaseDate CardSort = "release_date" // of the card's first printing
+	CardSortRelevance   CardSort = "relevance"
+)
+
+// CardSorts lists the card sort keys.
+var CardSorts = []CardSort{
+	CardSortName, CardSortCost, CardSortPower, CardSortDefense,
+	CardSortPitch, CardSortReleaseDate, CardSortRelevance,
+}
+
+// SetSort is the key set search results are sorted by.
+type SetSort string
+
+const (
+	// SetSortDefault leaves sets in file order.
+	SetSortDefault     SetSort = ""
+	SetSortName        SetSort = "name"
+	SetSortReleaseDate SetSort = "release_date" // of the set's first printing
+)
+
+// SetSorts lists the set sort keys.
+var SetSorts = []SetSort{SetSortName, SetSortReleaseDate}
+
+// SortOrder is the direction of a sort. The default is ascending, except for
+// relevance, which puts the best matches first.
+type SortOrder string
+
+const (
+	OrderDefault    SortOrder = ""
+	OrderAscending  SortOrder = "asc"
+	OrderDescending SortOrder = "desc"
+)
+
+// ParseCardSort parses a card sort key. An empty value selects the default.
+func ParseCardSort(value string) (CardSort, error) {
+	by := CardSort(strings.ToLower(value))
+	if by != CardSortDefault && !slices.Contains(CardSorts, by) {
+		return "", fmt.Errorf("invalid sort %q, expected one of %s", value, joinKeys(CardSorts))
+	}
+	return by, nil
+}
+
+// ParseSetSort parses a set sort key. An empty value selects the default.
+func ParseSetSort(value string) (SetSort, error) {
+	by := SetSort(strings.ToLower(value))
+	if by != SetSortDefault && !slices.Contains(SetSorts, by) {
+		return "", fmt.Errorf("invalid sort %q, expected one of %s", value, joinKeys(SetSorts))
+	}
+	return by, nil
+}
+
+// ParseSortOrder parses a sort order. An empty value selects the default.
+func ParseSortOrder(value string) (SortOrder, error) {
+	switch order := SortOrder(strings.ToLower(value)); order {
+	case OrderDefault, OrderAscending, OrderDescending:
+		return order, nil
+	default:
+		return "", fmt.Errorf("invalid order %q, expected asc or desc", value)
+	}
+}
+
+// ParseCardOrder parses the order of a card sort. The default sort keeps file
+// order, or relevance order for text searches, so it takes no order.
+func ParseCardOrder(by CardSort, value string) (SortOrder, error) {
+	if by == CardSortDefault && value != "" {
+		return "", fmt.Errorf("order %q needs a sort: without one, cards keep file order, or relevance order for text searches", value)
+	}
+	return ParseSortOrder(value)
+}
+
+// ParseSetOrder parses the order of a set sort. The default sort keeps file
+// order, so it takes no order.
+func ParseSetOrder(by SetSort, value string) (SortOrder, error) {
+	if by == SetSortDefault && value != "" {
+		return "", fmt.Errorf("order %q needs a sort: without one, sets keep file order", value)
+	}
+	return ParseSortOrder(value)
+}
+
+func joinKeys[K ~string](keys []K) string {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = string(key)
+	}
+	return strings.Join(names, ", ")
+}
+
+// sortKey orders cards by a numeric key. Cards are grouped by rank first,
+// whatever the order: numbers, then variable values such as "X", then cards
+// without the value.
+type sortKey struct {
+	rank  int
+	value float64
+}
+
+const (
+	rankValue = iota
+	rankVariable
+	rankMissing
+)
+
+func statSortKey(stat *domain.Stat) sortKey {
+	switch {
+	case stat == nil:
+		return sortKey{rank: rankMissing}
+	case stat.Variable:
+		return sortKey{rank: rankVariable}
+	default:
+		return sortKey{value: float64(stat.Value)}
+	}
+}
+
+func dateSortKey(date time.Time) sortKey {
+	if date.IsZero() {
+		return sortKey{rank: rankMissing}
+	}
+	return sortKey{value: float64(date.Unix())}
+}
+
+func (s *Store) cardSortKey(card domain.ScoredCard, by CardSort) sortKey {
+	switch by {
+	case CardSortCost:
+		return statSortKey(card.Stats.Cost)
+	case CardSortPower:
+		return statSortKey(card.Stats.Power)
+	case CardSortDefense:
+		return statSortKey(card.Stats.Defense)
+	case CardSortPitch:
+		return statSortKey(card.Stats.Pitch)
+	case CardSortReleaseDate:
+		return dateSortKey(s.ReleaseDateByCardID[card.UniqueID])
+	case CardSortRelevance:
+		return sortKey{value: card.Score}
+	default:
+		return sortKey{}
+	}
+}
+
+// resultKey locates a search result in the sort order: the sort key, then the
+// tiebreakers. Cursors carry the key of the result they resume from.
+type resultKey struct {
+	Rank    int     `json:"r,omitempty"`
+	Value   float64 `json:"v,omitempty"`
+	Name    string  `json:"n,omitempty"`
+	Pitch   string  `json:"p,omitempty"`
+	ID      string  `json:"i,omitempty"`
+	Ordinal int     `json:"o,omitempty"` // position in the data files
+}
+
+// cardKey returns the key of a card in results sorted by the given key. The
+// default order ranks by relevance score, which is zero without a text query,
+// then keeps file order.
+func (s *Store) cardKey(card domain.ScoredCard, by CardSort) resultKey {
+	switch by {
+	case CardSortDefault:
+		return resultKey{Value: card.Score, Ordinal: s.OrdinalByCardID[card.UniqueID]}
+	case CardSortName:
+		return resultKey{Name: card.Name, Pitch: card.Pitch, ID: card.UniqueID}
+	default:
+		k := s.cardSortKey(card, by)
+		return resultKey{Rank: k.rank, Value: k.value, Name: card.Name, Pitch: card.Pitch, ID: card.UniqueID}
+	}
+}
+
+// compareCardKeys orders card keys as sortCards does.
+func compareCardKeys(a, b resultKey, by CardSort, descending bool) int {
+	if by == CardSortDefault {
+		return cmp.Or(cmp.Compare(b.Value, a.Value), cmp.Compare(a.Ordinal, b.Ordinal))
+	}
+	if a.Rank != b.Rank {
+		return a.Rank - b.Rank
+	}
+
+	names := compareNames(a.Name, b.Name)
+	c := cmp.Compare(a.Value, b.Value)
+	if by == CardSortName {
+		c = names
+	}
+	if descending {
+		c = -c
+	}
+
+	return cmp.Or(c, names, strings.Compare(a.Pitch, b.Pitch), strings.Compare(a.ID, b.ID))
+}
+
+// descending reports whether results sorted by the key come in descending
+// order.
+func (by CardSort) descending(order SortOrder) bool {
+	return order == OrderDescending || (order == OrderDefault && by == CardSortRelevance)
+}
+
+// sortCards sorts search results. Ties are broken by name, pitch and unique
+// ID, so the order is total and pages never overlap.
+func (s *Store) sortCards(cards []domain.ScoredCard, by CardSort, order SortOrder) {
+	if by == CardSortDefault {
+		return
+	}
+	descending := by.descending(order)
+
+	slices.SortStableFunc(cards, func(a, b domain.ScoredCard) int {
+		return compareCardKeys(s.cardKey(a, by), s.cardKey(b, by), by, descending)
+	})
+}
+
+// compareNames orders names ignoring case, then by case so that the order is
+// total.
+func compareNames(a, b string) int {
+	return cmp.Or(strings.Compare(strings.ToLower(a), strings.ToLower(b)), strings.Compare(a, b))
+}
+
+// setReleaseDate returns the release date of a set's first printing.
+func setReleaseDate(set *domain.Set) time.Time {
+	var first time.Time
+	for _, printing := range set.Printings {
+		date, err := time.Parse(time.RFC3339, printing.InitialReleaseDate)
+		if err == nil && (first.IsZero() || date.Before(first)) {
+			first = date
+		}
+	}
+	return first
+}
+
+// setKey returns the key of a set in results sorted by the given key.
+func (s *Store) setKey(set *domain.Set, by SetSort) resultKey {
+	switch by {
+	case SetSortDefault:
+		return resultKey{Ordinal: s.OrdinalBySetID[set.ID]}
+	case SetSortReleaseDate:
+		k := dateSortKey(setReleaseDate(set))
+		return resultKey{Rank: k.rank, Value: k.value, Name: set.Name, ID: set.ID}
+	default:
+		return resultKey{Name: set.Name, ID: set.ID}
+	}
+}
+
+// compareSetKeys orders set keys as sortSets does.
+func compareSetKeys(a, b resultKey, by SetSort, descending bool) int {
+	if by == SetSortDefault {
+		return cmp.Compare(a.Ordinal, b.Ordinal)
+	}
+	if a.Rank != b.Rank {
+		return a.Rank - b.Rank
+	}
+
+	names := compareNames(a.Name, b.Name)
+	c := cmp.Compare(a.Value, b.Value)
+	if by == SetSortName {
+		c = names
+	}
+	if descending {
+		c = -c
+	}
+
+	return cmp.Or(c, names, strings.Compare(a.ID, b.ID))
+}
+
+// sortSets sorts set search results. Ties are broken by name and code.
+func (s *Store) sortSets(sets []*domain.Set, by SetSort, order SortOrder) {
+	if by == SetSortDefault {
+		return
+	}
+	descending := order == OrderDescending
+
+	keys := make(map[*domain.Set]resultKey, len(sets))
+	for _, set := range sets {
+		keys[set] = s.setKey(set, by)
+	}
+
+	slices.SortStableFunc(sets, func(a, b *domain.Set) int {
+		return compareSetKeys(keys[a], keys[b], by, descending)
+	})
+}
@@ -0,0 +1,64 @@
+package data
+
+import "github.com/oleiade/goagain/internal/domain"
+
+// StatRange bounds a numeric card stat. Nil bounds are open.
+type StatRange struct {
+	Min *int
+	Max *int
+}
+
+// StatBoundSuffixes are the suffixes of range filters such as "cost_min" or
+// "power_gte": "min" and "gte" set an inclusive lower bound, "max" and "lte"
+// an inclusive upper bound, "gt" and "lt" exclusive ones.
+var StatBoundSuffixes = []string{"min", "max", "gte", "lte", "gt", "lt"}
+
+// SetStatBound narrows the filter's range for a stat. The suffix is one of
+// StatBoundSuffixes; it reports false for any other.
+func (f *CardFilter) SetStatBound(stat domain.StatName, suffix string, value int) bool {
+	var lower bool
+	switch suffix {
+	case "min", "gte":
+		lower = true
+	case "gt":
+		lower, value = true, value+1
+	case "max", "lte":
+	case "lt":
+		value--
+	default:
+		return false
+	}
+
+	if f.StatRanges == nil {
+		f.StatRanges = make(map[domain.StatName]StatRange)
+	}
+
+	// Several bounds on the same side keep the narrowest
+	r := f.StatRanges[stat]
+	if lower && (r.Min == nil || value > *r.Min) {
+		r.Min = &value
+	} else if !lower && (r.Max == nil || value < *r.Max) {
+		r.Max = &value
+	}
+	f.StatRanges[stat] = r
+
+	return true
+}
+
+// Matches reports whether a stat falls within the range. Cards without the
+// stat never match. Variable stats such as "X" have no fixed value, so they
+// match only when includeVariable is set, whatever the bounds.
+func (r StatRange) Matches(stat *domain.Stat, includeVariable bool) bool {
+	switch {
+	case stat == nil:
+		return false
+	case stat.Variable:
+		return includeVariable
+	case r.Min != nil && stat.Value < *r.Min:
+		return false
+	case r.Max != nil && stat.Value > *r.Max:
+		return false
+	default:
+		return true
+	}
+}
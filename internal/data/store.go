@@ -2,14 +2,22 @@
 package data
 
 import (
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"hash"
+	"io/fs"
+	"os"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/oleiade/goagain/internal/domain"
 	"github.com/oleiade/goagain/internal/observability"
+	"github.com/oleiade/goagain/internal/query"
+	"github.com/oleiade/goagain/internal/render"
 )
 
 //go:embed english/*.json
@@ -17,12 +25,36 @@ var embeddedData embed.FS
 
 // Store holds all loaded card data with indexes for efficient lookup.
 type Store struct {
+	// Version identifies the loaded data: a digest of every source file, so
+	// the same files give the same version whether embedded or read from disk.
+	Version  string
+	LoadedAt time.Time
+
+	fsys   fs.FS
+	digest hash.Hash
+
 	Cards     []*domain.Card
 	Sets      []*domain.Set
 	Keywords  []*domain.Keyword
 	Abilities []*domain.Ability
 	Types     []*domain.Type
 
+	LegalityEvents []*domain.LegalityEvent
+	CardReferences []*domain.CardReference
+
+	FaceAssociations []*domain.FaceAssociation
+	Printings        []*domain.CardPrinting
+
+	Rarities      []*domain.Rarity
+	Foilings      []*domain.Foiling
+	Editions      []*domain.Edition
+	ArtVariations []*domain.ArtVariation
+	Artists       []*domain.Artist
+	Icons         []*domain.Icon
+
+	// Renderer expands the icon tokens in card text
+	Renderer *render.Renderer
+
 	// Indexes
 	CardsByID      map[string]*domain.Card
 	CardsByName    map[string][]*domain.Card // Multiple cards can share a name (different pitches)
@@ -35,11 +67,72 @@ type Store struct {
 	CardsByClass   map[string][]*domain.Card
 	CardsByType    map[string][]*domain.Card
 	CardsByKeyword map[string][]*domain.Card
+
+	// Legality timelines, keyed by card unique ID
+	LegalityByCardID map[string]domain.LegalityHistory
+
+	// Earliest release date across a card's printings, keyed by card unique ID
+	ReleaseDateByCardID map[string]time.Time
+
+	// Position of each card and set in the data files, keyed by card unique ID
+	// and set code, for resuming file-ordered results from a cursor
+	OrdinalByCardID map[string]int
+	OrdinalBySetID  map[string]int
+
+	// Reference graph adjacency, keyed by card unique ID
+	ReferencesByCardID map[string][]string
+	ReferencedByCardID map[string][]string
+
+	// Inverted index over card text for ranked search
+	TextIndex *TextIndex
+
+	// Normalized card names for autocomplete and typo-tolerant lookup
+	Names *NameIndex
+
+	// Bitmaps of the cards with each class, type, keyword, set, pitch,
+	// rarity, color and format legality, intersected by searches
+	Bitmaps *BitmapIndex
+
+	// cache holds search results when the store is served by a provider
+	cache *ResultCache
+
+	// Card-level face links and the logical card each face belongs to, keyed by card unique ID
+	FaceLinksByCardID map[string][]domain.FaceAssociation
+	FaceGroupByCardID map[string]string
+
+	// Printings by unique ID and by collector ID (e.g., "WTR001", upper-cased)
+	PrintingsByUniqueID map[string]*domain.CardPrinting
+	PrintingsByID       map[string][]*domain.CardPrinting
+
+	// Printing code lookup tables, keyed by code (e.g., "R", "C", "AA")
+	RaritiesByID      map[string]*domain.Rarity
+	FoilingsByID      map[string]*domain.Foiling
+	EditionsByID      map[string]*domain.Edition
+	ArtVariationsByID map[string]*domain.ArtVariation
+
+	// Artists and the printings they are credited on, keyed by lower-cased name
+	ArtistsByName     map[string]*domain.Artist
+	PrintingsByArtist map[string][]*domain.CardPrinting
 }
 
 // NewStore creates and initializes a new data store from embedded JSON files.
 func NewStore(metrics *observability.Metrics) (*Store, error) {
+	return NewStoreFromFS(embeddedFS(), metrics)
+}
+
+// NewStoreFromDir creates and initializes a new data store from the JSON files
+// in dir, laid out like the embedded english directory.
+func NewStoreFromDir(dir string, metrics *observability.Metrics) (*Store, error) {
+	return NewStoreFromFS(os.DirFS(dir), metrics)
+}
+
+// NewStoreFromFS creates and initializes a new data store from the JSON files
+// at the root of fsys.
+func NewStoreFromFS(fsys fs.FS, metrics *observability.Metrics) (*Store, error) {
 	s := &Store{
+		fsys:   fsys,
+		digest: sha256.New(),
+
 		CardsByID:      make(map[string]*domain.Card),
 		CardsByName:    make(map[string][]*domain.Card),
 		CardsBySetID:   make(map[string][]*domain.Card),
@@ -49,6 +142,21 @@ func NewStore(metrics *observability.Metrics) (*Store, error) {
 		CardsByClass:   make(map[string][]*domain.Card),
 		CardsByType:    make(map[string][]*domain.Card),
 		CardsByKeyword: make(map[string][]*domain.Card),
+
+		LegalityByCardID:    make(map[string]domain.LegalityHistory),
+		ReleaseDateByCardID: make(map[string]time.Time),
+		ReferencesByCardID:  make(map[string][]string),
+		ReferencedByCardID:  make(map[string][]string),
+		FaceLinksByCardID:   make(map[string][]domain.FaceAssociation),
+		FaceGroupByCardID:   make(map[string]string),
+		PrintingsByUniqueID: make(map[string]*domain.CardPrinting),
+		PrintingsByID:       make(map[string][]*domain.CardPrinting),
+		RaritiesByID:        make(map[string]*domain.Rarity),
+		FoilingsByID:        make(map[string]*domain.Foiling),
+		EditionsByID:        make(map[string]*domain.Edition),
+		ArtVariationsByID:   make(map[string]*domain.ArtVariation),
+		ArtistsByName:       make(map[string]*domain.Artist),
+		PrintingsByArtist:   make(map[string][]*domain.CardPrinting),
 	}
 
 	if err := s.loadTypes(); err != nil {
@@ -71,6 +179,42 @@ func NewStore(metrics *observability.Metrics) (*Store, error) {
 		return nil, fmt.Errorf("loading abilities: %w", err)
 	}
 
+	if err := s.loadLegality(); err != nil {
+		return nil, fmt.Errorf("loading legality: %w", err)
+	}
+
+	if err := s.loadCardReferences(); err != nil {
+		return nil, fmt.Errorf("loading card references: %w", err)
+	}
+
+	if err := s.loadFaceAssociations(); err != nil {
+		return nil, fmt.Errorf("loading face associations: %w", err)
+	}
+
+	if err := s.loadCodes(); err != nil {
+		return nil, fmt.Errorf("loading printing codes: %w", err)
+	}
+
+	if err := s.loadArtists(); err != nil {
+		return nil, fmt.Errorf("loading artists: %w", err)
+	}
+
+	if err := s.loadIcons(); err != nil {
+		return nil, fmt.Errorf("loading icons: %w", err)
+	}
+
+	s.indexReleaseDates()
+	s.indexOrdinals()
+	s.indexPrintings()
+	s.indexFaces()
+	s.indexArtists()
+	s.TextIndex = NewTextIndex(s.Cards)
+	s.Names = NewNameIndex(s.Cards)
+	s.Bitmaps = NewBitmapIndex(s.Cards)
+
+	s.Version = hex.EncodeToString(s.digest.Sum(nil))[:16]
+	s.LoadedAt = time.Now().UTC()
+
 	// After all data is loaded and indexed, set the metrics
 	if metrics != nil {
 		stats, indexStats := s.Stats()
@@ -81,8 +225,37 @@ func NewStore(metrics *observability.Metrics) (*Store, error) {
 	return s, nil
 }
 
+// embeddedFS returns the embedded english directory as the root of a file system.
+func embeddedFS() fs.FS {
+	fsys, err := fs.Sub(embeddedData, "english")
+	if err != nil {
+		panic(err) // the directory is embedded, so this cannot fail
+	}
+	return fsys
+}
+
+// readFile reads a data file, adding it to the store's version digest. Stores
+// built without a file system read the embedded files.
+func (s *Store) readFile(name string) ([]byte, error) {
+	if s.fsys == nil {
+		s.fsys = embeddedFS()
+	}
+
+	data, err := fs.ReadFile(s.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.digest != nil {
+		s.digest.Write([]byte(name))
+		s.digest.Write(data)
+	}
+
+	return data, nil
+}
+
 func (s *Store) loadCards() error {
-	data, err := embeddedData.ReadFile("english/card.json")
+	data, err := s.readFile("card.json")
 	if err != nil {
 		return fmt.Errorf("reading card.json: %w", err)
 	}
@@ -96,6 +269,7 @@ func (s *Store) loadCards() error {
 
 	// Build indexes
 	for _, card := range cards {
+		card.ParseStats()
 		s.CardsByID[card.UniqueID] = card
 
 		nameLower := strings.ToLower(card.Name)
@@ -106,7 +280,7 @@ func (s *Store) loadCards() error {
 		}
 
 		// Build new indexes
-		if cardClass := card.GetClass(); cardClass != "" {
+		for _, cardClass := range card.Classes() {
 			s.CardsByClass[cardClass] = append(s.CardsByClass[cardClass], card)
 		}
 
@@ -123,7 +297,7 @@ func (s *Store) loadCards() error {
 }
 
 func (s *Store) loadSets() error {
-	data, err := embeddedData.ReadFile("english/set.json")
+	data, err := s.readFile("set.json")
 	if err != nil {
 		return fmt.Errorf("reading set.json: %w", err)
 	}
@@ -143,7 +317,7 @@ func (s *Store) loadSets() error {
 }
 
 func (s *Store) loadKeywords() error {
-	data, err := embeddedData.ReadFile("english/keyword.json")
+	data, err := s.readFile("keyword.json")
 	if err != nil {
 		return fmt.Errorf("reading keyword.json: %w", err)
 	}
@@ -163,7 +337,7 @@ func (s *Store) loadKeywords() error {
 }
 
 func (s *Store) loadAbilities() error {
-	data, err := embeddedData.ReadFile("english/ability.json")
+	data, err := s.readFile("ability.json")
 	if err != nil {
 		return fmt.Errorf("reading ability.json: %w", err)
 	}
@@ -178,7 +352,7 @@ func (s *Store) loadAbilities() error {
 }
 
 func (s *Store) loadTypes() error {
-	data, err := embeddedData.ReadFile("english/type.json")
+	data, err := s.readFile("type.json")
 	if err != nil {
 		return fmt.Errorf("reading type.json: %w", err)
 	}
@@ -195,6 +369,57 @@ func (s *Store) loadTypes() error {
 	return nil
 }
 
+// indexOrdinals records the position of each card and set in the data files.
+func (s *Store) indexOrdinals() {
+	s.OrdinalByCardID = make(map[string]int, len(s.Cards))
+	for i, card := range s.Cards {
+		s.OrdinalByCardID[card.UniqueID] = i
+	}
+
+	s.OrdinalBySetID = make(map[string]int, len(s.Sets))
+	for i, set := range s.Sets {
+		s.OrdinalBySetID[set.ID] = i
+	}
+}
+
+// indexReleaseDates records when each card was first released, using the
+// initial release date of the matching set printing (or of the set itself
+// when no edition matches).
+func (s *Store) indexReleaseDates() {
+	for _, card := range s.Cards {
+		var first time.Time
+		for _, printing := range card.Printings {
+			set := s.SetsByID[printing.SetID]
+			if set == nil {
+				continue
+			}
+
+			var released time.Time
+			for _, setPrinting := range set.Printings {
+				date, err := time.Parse(time.RFC3339, setPrinting.InitialReleaseDate)
+				if err != nil {
+					continue
+				}
+				if setPrinting.Edition == printing.Edition {
+					released = date
+					break
+				}
+				if released.IsZero() || date.Before(released) {
+					released = date
+				}
+			}
+
+			if !released.IsZero() && (first.IsZero() || released.Before(first)) {
+				first = released
+			}
+		}
+
+		if !first.IsZero() {
+			s.ReleaseDateByCardID[card.UniqueID] = first
+		}
+	}
+}
+
 // GetCardByID returns a card by its unique ID.
 func (s *Store) GetCardByID(id string) *domain.Card {
 	return s.CardsByID[id]
@@ -222,139 +447,247 @@ type CardFilter struct {
 	Class     string
 	SetID     string
 	Pitch     string
-	Keyword   string
+	Keyword   string // Partial match on keyword names
 	TextQuery string
 	LegalIn   domain.Format
-	Limit     int
-	Offset    int
+	LegalAsOf time.Time // Evaluate LegalIn at this date instead of today
+
+	// Values holds filters of several values, such as any of two classes or
+	// all of three keywords. Cards must pass every one of them, as well as
+	// the single value fields above.
+	Values []ValueFilter
+
+	// Query is a parsed query language expression the cards must also match
+	Query query.Node
+
+	// StatRanges bounds numeric stats, set with SetStatBound. Variable stats
+	// such as "X" only match when IncludeVariable is set.
+	StatRanges      map[domain.StatName]StatRange
+	IncludeVariable bool
+
+	// Sort and Order arrange the results; see CardSort
+	Sort  CardSort
+	Order SortOrder
+
+	// CollapseFaces lists multi-faced cards once, by their front face
+	CollapseFaces bool
+
+	Limit  int
+	Offset int
+	Cursor string // From a previous page, see SearchCardsPage; replaces Offset
 }
 
 // SearchCards searches for cards matching the given filter criteria.
 // It returns the paginated results and the total number of matches.
 func (s *Store) SearchCards(filter CardFilter) ([]*domain.Card, int) {
-	var results []*domain.Card
+	scored, total := s.SearchCardsScored(filter)
+	if scored == nil {
+		return nil, total
+	}
 
-	// Use indexes to get an initial, smaller set of candidates
-	var candidates []*domain.Card
-	var usingIndex bool
-
-	if filter.Class != "" {
-		candidates = s.CardsByClass[filter.Class]
-		usingIndex = true
-	} else if filter.Type != "" {
-		candidates = s.CardsByType[filter.Type]
-		usingIndex = true
-	} else if filter.Keyword != "" {
-		// Keyword filter is partial, so we find the first matching keyword
-		// This is still better than a full scan.
-		for kw, cards := range s.CardsByKeyword {
-			if strings.Contains(strings.ToLower(kw), strings.ToLower(filter.Keyword)) {
-				// We can't just use this, we need to merge if multiple keywords match
-				// For simplicity, we'll take the first match for now. A more complex
-				// solution would merge and deduplicate.
-				candidates = cards
-				usingIndex = true
-				break
+	cards := make([]*domain.Card, len(scored))
+	for i, card := range scored {
+		cards[i] = card.Card
+	}
+	return cards, total
+}
+
+// SearchCardsScored is SearchCards with relevance scores. When the filter
+// has a text query, results are ranked by score, most relevant first.
+func (s *Store) SearchCardsScored(filter CardFilter) ([]domain.ScoredCard, int) {
+	results, _ := s.matchCards(filter)
+	return slices.Clone(paginate(results, filter.Offset, filter.Limit)), len(results)
+}
+
+// matchCards returns every card matching the filter, sorted but not
+// paginated, and whether they came from the result cache. Cached results are
+// shared, so callers copy them before making changes.
+func (s *Store) matchCards(filter CardFilter) ([]domain.ScoredCard, bool) {
+	if s.cache == nil {
+		return s.findCards(filter), false
+	}
+
+	key := filter.cacheKey(s.Version)
+	if results, ok := s.cache.get(s, key); ok {
+		return results, true
+	}
+	results := s.findCards(filter)
+	s.cache.put(s, key, results)
+	return results, false
+}
+
+// findCards searches the cards matching the filter and sorts them.
+func (s *Store) findCards(filter CardFilter) []domain.ScoredCard {
+	var results []domain.ScoredCard
+
+	m := s.newCardMatcher(filter)
+	seenFaceGroups := make(map[string]bool)
+	substituted := false
+	add := func(card *domain.Card, ordinal int, score float64) {
+		if !m.matches(card, ordinal) {
+			return
+		}
+		if filter.CollapseFaces {
+			if group, ok := s.FaceGroupByCardID[card.UniqueID]; ok {
+				if seenFaceGroups[group] {
+					return
+				}
+				seenFaceGroups[group] = true
+
+				// The logical card is listed by its front face, whichever
+				// face matched
+				if front := s.CardsByID[group]; front != nil && front != card {
+					card = front
+					substituted = true
+				}
 			}
 		}
-	} else if filter.SetID != "" {
-		candidates = s.CardsBySetID[strings.ToUpper(filter.SetID)]
-		usingIndex = true
+		results = append(results, domain.ScoredCard{Card: card, Score: score})
 	}
 
-	// If no index was used, fall back to a full scan
-	if !usingIndex {
-		candidates = s.Cards
+	switch {
+	case filter.TextQuery != "" && s.TextIndex != nil && hasTextClauses(filter.TextQuery):
+		// Text matches come ranked, so they set the order of the results.
+		// A text query without words, such as "!!", filters nothing.
+		for _, hit := range s.TextIndex.Search(filter.TextQuery) {
+			if m.cards == nil || m.cards.Contains(hit.Ordinal) {
+				add(hit.Card, hit.Ordinal, hit.Score)
+			}
+		}
+	case m.cards != nil:
+		// Only the cards passing every indexed filter are looked at
+		for ordinal := range m.cards.All() {
+			add(s.Cards[ordinal], ordinal, 0)
+		}
+	default:
+		for ordinal, card := range s.Cards {
+			add(card, ordinal, 0)
+		}
 	}
 
-	// Now, filter the candidates
-	for _, card := range candidates {
-		if !s.matchesFilter(card, filter) {
-			continue
-		}
-		results = append(results, card)
+	// A front face listed in place of the face that matched takes the front
+	// face's place in the default order, which cursors resume by
+	if substituted && filter.Sort == CardSortDefault {
+		slices.SortStableFunc(results, func(a, b domain.ScoredCard) int {
+			return compareCardKeys(s.cardKey(a, CardSortDefault), s.cardKey(b, CardSortDefault), CardSortDefault, false)
+		})
 	}
 
-	total := len(results)
+	s.sortCards(results, filter.Sort, filter.Order)
+	return results
+}
 
-	// Apply pagination
-	if filter.Offset > 0 {
-		if filter.Offset >= len(results) {
-			return nil, total // Page is out of bounds
+// paginate returns a page of results. Pages past the end are nil, and a zero
+// limit returns every result from the offset on.
+func paginate[T any](results []T, offset, limit int) []T {
+	if offset > 0 {
+		if offset >= len(results) {
+			return nil // Page is out of bounds
 		}
-		results = results[filter.Offset:]
+		results = results[offset:]
 	}
 
-	if filter.Limit > 0 && len(results) > filter.Limit {
-		results = results[:filter.Limit]
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
 	}
 
-	return results, total
+	return results
 }
 
-func (s *Store) matchesFilter(card *domain.Card, filter CardFilter) bool {
-	// Name filter (partial match)
-	if filter.Name != "" {
-		if !strings.Contains(strings.ToLower(card.Name), strings.ToLower(filter.Name)) {
-			return false
-		}
+// cardMatcher checks cards against a filter, with the filter values prepared
+// once per search. The checks the bitmap index answers exactly are left to
+// the index.
+type cardMatcher struct {
+	store  *Store
+	filter CardFilter
+
+	// cards holds the cards passing the indexed checks, nil when there is
+	// no index or nothing it can narrow
+	cards *Bitmap
+
+	name     string        // normalized name filter
+	text     string        // lower-cased text query, without a text index
+	values   []ValueFilter // value filters the index did not answer
+	query    bool          // whether to check the query per card
+	legality bool          // whether to check legality per card
+}
+
+func (s *Store) newCardMatcher(filter CardFilter) *cardMatcher {
+	m := &cardMatcher{
+		store:    s,
+		filter:   filter,
+		name:     normalizeName(filter.Name),
+		values:   filter.valueFilters(),
+		query:    filter.Query != nil,
+		legality: filter.LegalIn != "",
+	}
+	if filter.TextQuery != "" && s.TextIndex == nil {
+		m.text = strings.ToLower(filter.TextQuery)
 	}
 
-	// Type filter (if not already handled by index)
-	if filter.Type != "" && s.CardsByType[filter.Type] == nil {
-		if !card.HasType(filter.Type) {
-			return false
+	if s.Bitmaps != nil {
+		m.cards = s.Bitmaps.match(filter)
+		m.values = nil
+		m.legality = filter.LegalIn != "" && !filter.LegalAsOf.IsZero()
+
+		if filter.Query != nil {
+			cards, exact := s.Bitmaps.matchQuery(filter.Query, s.RaritiesByID)
+			m.cards = intersect(m.cards, cards)
+			m.query = !exact
 		}
 	}
 
-	// Class filter (if not already handled by index)
-	if filter.Class != "" && s.CardsByClass[filter.Class] == nil {
-		if !strings.EqualFold(card.GetClass(), filter.Class) {
+	return m
+}
+
+// matches reports whether a card at an ordinal of the store's cards passes
+// the checks left after the index.
+func (m *cardMatcher) matches(card *domain.Card, ordinal int) bool {
+	filter := m.filter
+
+	// Name filter (partial match, ignoring case, punctuation and diacritics)
+	if m.name != "" {
+		name := ""
+		if m.store.Bitmaps != nil {
+			name = m.store.Bitmaps.names[ordinal]
+		} else {
+			name = normalizeName(card.Name)
+		}
+		if !strings.Contains(name, m.name) {
 			return false
 		}
 	}
 
-	// Set filter (if not already handled by index)
-	if filter.SetID != "" && s.CardsBySetID[strings.ToUpper(filter.SetID)] == nil {
-		found := false
-		for _, printing := range card.Printings {
-			if strings.EqualFold(printing.SetID, filter.SetID) {
-				found = true
-				break
-			}
-		}
-		if !found {
+	// Class, type, keyword, set and pitch filters
+	for _, f := range m.values {
+		if !f.Matches(card) {
 			return false
 		}
 	}
 
-	// Pitch filter
-	if filter.Pitch != "" && card.Pitch != filter.Pitch {
+	// Text search (if not already handled by the text index)
+	if m.text != "" && !strings.Contains(strings.ToLower(card.FunctionalTextPlain), m.text) {
 		return false
 	}
 
-	// Keyword filter (more thorough than the index check)
-	if filter.Keyword != "" {
-		hasKeyword := slices.ContainsFunc(card.CardKeywords, func(k string) bool {
-			return strings.Contains(strings.ToLower(k), strings.ToLower(filter.Keyword))
-		})
-		if !hasKeyword {
+	// Numeric stat ranges
+	for stat, bounds := range filter.StatRanges {
+		if !bounds.Matches(card.Stats.Get(stat), filter.IncludeVariable) {
 			return false
 		}
 	}
 
-	// Text search
-	if filter.TextQuery != "" {
-		query := strings.ToLower(filter.TextQuery)
-		text := strings.ToLower(card.FunctionalTextPlain)
-		if !strings.Contains(text, query) {
-			return false
-		}
+	// Query language expression
+	if m.query && !m.store.matchesQuery(card, filter.Query) {
+		return false
 	}
 
 	// Format legality filter
-	if filter.LegalIn != "" {
+	if m.legality {
 		legality := card.GetLegality(filter.LegalIn)
+		if !filter.LegalAsOf.IsZero() {
+			legality = m.store.GetLegalityAt(card, filter.LegalIn, filter.LegalAsOf)
+		}
 		if !legality.Legal {
 			return false
 		}
@@ -368,6 +701,14 @@ type SetFilter struct {
 	Name  string // Partial match on set name
 	ID    string // Partial match on set code
 	Query string // Search both name and ID
+
+	// Sort and Order arrange the results; see SetSort
+	Sort  SetSort
+	Order SortOrder
+
+	// Limit and Cursor page through the results with SearchSetsPage
+	Limit  int
+	Cursor string
 }
 
 // SearchSets searches for sets matching the given filter criteria.
@@ -381,6 +722,7 @@ func (s *Store) SearchSets(filter SetFilter) []*domain.Set {
 		results = append(results, set)
 	}
 
+	s.sortSets(results, filter.Sort, filter.Order)
 	return results
 }
 
@@ -436,6 +778,19 @@ func (s *Store) Stats() (map[string]int, map[string]int) {
 		"keywords":  len(s.Keywords),
 		"abilities": len(s.Abilities),
 		"types":     len(s.Types),
+
+		"legality_events": len(s.LegalityEvents),
+		"card_references": len(s.CardReferences),
+
+		"face_associations": len(s.FaceAssociations),
+		"printings":         len(s.Printings),
+
+		"rarities":       len(s.Rarities),
+		"foilings":       len(s.Foilings),
+		"editions":       len(s.Editions),
+		"art_variations": len(s.ArtVariations),
+		"artists":        len(s.Artists),
+		"icons":          len(s.Icons),
 	}
 
 	indexStats := map[string]int{
@@ -448,6 +803,30 @@ func (s *Store) Stats() (map[string]int, map[string]int) {
 		"cards_by_class":   len(s.CardsByClass),
 		"cards_by_type":    len(s.CardsByType),
 		"cards_by_keyword": len(s.CardsByKeyword),
+
+		"legality_by_card_id":     len(s.LegalityByCardID),
+		"release_date_by_card_id": len(s.ReleaseDateByCardID),
+		"references_by_card_id":   len(s.ReferencesByCardID),
+		"referenced_by_card_id":   len(s.ReferencedByCardID),
+		"face_links_by_card_id":   len(s.FaceLinksByCardID),
+		"face_group_by_card_id":   len(s.FaceGroupByCardID),
+		"printings_by_unique_id":  len(s.PrintingsByUniqueID),
+		"printings_by_id":         len(s.PrintingsByID),
+		"rarities_by_id":          len(s.RaritiesByID),
+		"foilings_by_id":          len(s.FoilingsByID),
+		"editions_by_id":          len(s.EditionsByID),
+		"art_variations_by_id":    len(s.ArtVariationsByID),
+		"artists_by_name":         len(s.ArtistsByName),
+		"printings_by_artist":     len(s.PrintingsByArtist),
+	}
+	if s.TextIndex != nil {
+		indexStats["text_index_terms"] = len(s.TextIndex.vocabulary)
+	}
+	if s.Names != nil {
+		indexStats["name_index_names"] = len(s.Names.entries)
+	}
+	if s.Bitmaps != nil {
+		indexStats["bitmap_index_values"] = s.Bitmaps.Len()
 	}
 
 	return dataStats, indexStats
@@ -1,9 +1,23 @@
 package data
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/oleiade/goagain/internal/domain"
+	"github.com/oleiade/goagain/internal/query"
+	"github.com/oleiade/goagain/internal/render"
 )
 
 func TestNewStore(t *testing.T) {
@@ -239,4 +253,1686 @@ func TestCardLegality(t *testing.T) {
 	if !legality.Legal {
 		t.Errorf("Card %q should be legal in Blitz", card.Name)
 	}
-}
\ No newline at end of file
+}
+
+func TestLoadLegality(t *testing.T) {
+	store := &Store{LegalityByCardID: make(map[string]domain.LegalityHistory)}
+	if err := store.loadLegality(); err != nil {
+		t.Fatalf("loadLegality() error = %v", err)
+	}
+
+	if len(store.LegalityEvents) == 0 {
+		t.Fatal("Expected legality events to be loaded")
+	}
+
+	for _, event := range store.LegalityEvents {
+		if event.Format == "" || event.Status == "" {
+			t.Fatalf("Event %s is missing its format or status", event.UniqueID)
+		}
+		if event.DateInEffect.IsZero() {
+			t.Errorf("Event %s has no effective date", event.UniqueID)
+		}
+	}
+
+	for cardID, history := range store.LegalityByCardID {
+		for format, timeline := range history {
+			for i := 1; i < len(timeline); i++ {
+				if timeline[i].DateInEffect.Before(timeline[i-1].DateInEffect) {
+					t.Errorf("Timeline for card %s in %s is not ordered by effective date", cardID, format)
+				}
+			}
+		}
+	}
+}
+
+func TestGetLegalityAt(t *testing.T) {
+	store := &Store{
+		LegalityByCardID:    make(map[string]domain.LegalityHistory),
+		ReleaseDateByCardID: make(map[string]time.Time),
+	}
+	if err := store.loadLegality(); err != nil {
+		t.Fatalf("loadLegality() error = %v", err)
+	}
+
+	// This card was banned in Blitz in 2021, unbanned in May 2023 and banned again in August 2023.
+	card := &domain.Card{UniqueID: "9bQFfTFGQWJFWrcJnfQgz", BlitzLegal: true}
+	store.ReleaseDateByCardID[card.UniqueID] = time.Date(2019, 10, 11, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		date       string
+		wantLegal  bool
+		wantBanned bool
+	}{
+		{date: "2019-01-01", wantLegal: false, wantBanned: false},
+		{date: "2020-06-01", wantLegal: true, wantBanned: false},
+		{date: "2021-03-26", wantLegal: false, wantBanned: true},
+		{date: "2023-06-01", wantLegal: true, wantBanned: false},
+		{date: "2024-01-01", wantLegal: false, wantBanned: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			asOf, err := domain.ParseDate(tt.date)
+			if err != nil {
+				t.Fatalf("ParseDate(%q) error = %v", tt.date, err)
+			}
+
+			legality := store.GetLegalityAt(card, domain.FormatBlitz, asOf)
+			if legality.Legal != tt.wantLegal || legality.Banned != tt.wantBanned {
+				t.Errorf("GetLegalityAt(%s) = legal %v, banned %v; want legal %v, banned %v",
+					tt.date, legality.Legal, legality.Banned, tt.wantLegal, tt.wantBanned)
+			}
+		})
+	}
+}
+
+func TestGetBanList(t *testing.T) {
+	store := &Store{
+		CardsByID:        make(map[string]*domain.Card),
+		LegalityByCardID: make(map[string]domain.LegalityHistory),
+	}
+	if err := store.loadLegality(); err != nil {
+		t.Fatalf("loadLegality() error = %v", err)
+	}
+
+	format, _ := domain.GetFormatInfo("blitz")
+	asOf := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	banList := store.GetBanList(format, asOf)
+
+	if len(banList.Banned) == 0 {
+		t.Fatal("Expected cards to be banned in Blitz at the start of 2022")
+	}
+	for _, entry := range banList.Banned {
+		if entry.DateInEffect.After(asOf) {
+			t.Errorf("Card %s is listed before its ban took effect on %s", entry.CardUniqueID, entry.DateInEffect)
+		}
+		if entry.LegalityArticle == "" {
+			t.Errorf("Card %s has no legality article", entry.CardUniqueID)
+		}
+	}
+
+	// Living legend status in Blitz was only introduced in May 2022.
+	if len(banList.LivingLegend) != 0 {
+		t.Errorf("Expected no living legends in Blitz at the start of 2022, got %d", len(banList.LivingLegend))
+	}
+}
+
+func TestGetLegalityChanges(t *testing.T) {
+	store := &Store{
+		CardsByID:        make(map[string]*domain.Card),
+		LegalityByCardID: make(map[string]domain.LegalityHistory),
+	}
+	if err := store.loadLegality(); err != nil {
+		t.Fatalf("loadLegality() error = %v", err)
+	}
+
+	since := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)
+	changes := store.GetLegalityChanges(LegalityChangeFilter{Since: since, Until: until, Format: domain.FormatCC})
+
+	if len(changes) == 0 {
+		t.Fatal("Expected legality changes in Classic Constructed during 2023")
+	}
+	for i, change := range changes {
+		if change.Format != domain.FormatCC {
+			t.Errorf("Change %s is for format %s, want cc", change.UniqueID, change.Format)
+		}
+		if change.DateInEffect.Before(since) && change.DateAnnounced.Before(since) {
+			t.Errorf("Change %s falls before the window", change.UniqueID)
+		}
+		if i > 0 && change.DateInEffect.Before(changes[i-1].DateInEffect) {
+			t.Error("Changes are not ordered by effective date")
+		}
+		if change.Scheduled {
+			t.Errorf("Change %s from 2023 should not be scheduled", change.UniqueID)
+		}
+	}
+}
+
+func TestLegalityReasons(t *testing.T) {
+	store := &Store{
+		LegalityByCardID:    make(map[string]domain.LegalityHistory),
+		ReleaseDateByCardID: make(map[string]time.Time),
+	}
+	if err := store.loadLegality(); err != nil {
+		t.Fatalf("loadLegality() error = %v", err)
+	}
+
+	card := &domain.Card{UniqueID: "9bQFfTFGQWJFWrcJnfQgz", BlitzLegal: true}
+	store.ReleaseDateByCardID[card.UniqueID] = time.Date(2019, 10, 11, 0, 0, 0, 0, time.UTC)
+
+	legality := store.GetLegalityAt(card, domain.FormatBlitz, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	if len(legality.Reasons) != 1 {
+		t.Fatalf("Expected a single reason, got %+v", legality.Reasons)
+	}
+
+	reason := legality.Reasons[0]
+	if reason.Code != domain.ReasonBanned {
+		t.Errorf("Reason code = %q, want %q", reason.Code, domain.ReasonBanned)
+	}
+	if reason.Start == nil || reason.Start.Format(time.DateOnly) != "2021-03-26" {
+		t.Errorf("Reason start = %v, want 2021-03-26", reason.Start)
+	}
+	if reason.End == nil || reason.End.Format(time.DateOnly) != "2023-05-05" {
+		t.Errorf("Reason end = %v, want 2023-05-05", reason.End)
+	}
+	if reason.Article == "" {
+		t.Error("Expected the reason to carry its announcement article")
+	}
+
+	legality = store.GetLegalityAt(card, domain.FormatCommoner, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	codes := make([]domain.ReasonCode, len(legality.Reasons))
+	for i, reason := range legality.Reasons {
+		codes[i] = reason.Code
+	}
+	want := []domain.ReasonCode{domain.ReasonNotReleased, domain.ReasonNotInFormat}
+	if !slices.Equal(codes, want) {
+		t.Errorf("Reason codes = %v, want %v", codes, want)
+	}
+}
+
+func TestGetCardGraph(t *testing.T) {
+	// A hero, a card naming it and the token that card creates.
+	store := &Store{
+		CardsByID:          make(map[string]*domain.Card),
+		ReferencesByCardID: map[string][]string{"attack": {"hero", "token"}},
+		ReferencedByCardID: map[string][]string{"hero": {"attack"}, "token": {"attack"}},
+	}
+	for _, id := range []string{"hero", "attack", "token"} {
+		store.CardsByID[id] = &domain.Card{UniqueID: id, Name: id}
+	}
+
+	graph := store.GetCardGraph(store.CardsByID["hero"], 1, domain.GraphReferencedBy)
+	if len(graph.Nodes) != 2 || len(graph.Edges) != 1 {
+		t.Errorf("Depth 1 graph has %d nodes and %d edges, want 2 and 1", len(graph.Nodes), len(graph.Edges))
+	}
+
+	graph = store.GetCardGraph(store.CardsByID["hero"], 2, domain.GraphBoth)
+	if len(graph.Nodes) != 3 || len(graph.Edges) != 2 {
+		t.Errorf("Depth 2 graph has %d nodes and %d edges, want 3 and 2", len(graph.Nodes), len(graph.Edges))
+	}
+	if graph.Nodes[2].UniqueID != "token" || graph.Nodes[2].Depth != 2 {
+		t.Errorf("Expected the token at depth 2, got %+v", graph.Nodes[2])
+	}
+}
+
+func TestCardFaces(t *testing.T) {
+	store := &Store{
+		CardsByID:           make(map[string]*domain.Card),
+		FaceLinksByCardID:   make(map[string][]domain.FaceAssociation),
+		FaceGroupByCardID:   make(map[string]string),
+		PrintingsByUniqueID: make(map[string]*domain.CardPrinting),
+		PrintingsByID:       make(map[string][]*domain.CardPrinting),
+	}
+	if err := store.loadFaceAssociations(); err != nil {
+		t.Fatalf("loadFaceAssociations() error = %v", err)
+	}
+	if len(store.FaceAssociations) == 0 {
+		t.Fatal("Expected face associations to be loaded")
+	}
+
+	// A back face listed first, its front, an unrelated card, a second back
+	// branching off the front, and another unrelated card.
+	for _, id := range []string{"back", "front", "middle", "other-back", "single"} {
+		card := &domain.Card{UniqueID: id, Name: id, Printings: []domain.Printing{{UniqueID: id + "-printing"}}}
+		store.Cards = append(store.Cards, card)
+		store.CardsByID[id] = card
+	}
+	store.FaceAssociations = []*domain.FaceAssociation{
+		{FrontUniqueID: "front-printing", BackUniqueID: "back-printing", IsDFC: true},
+		{FrontUniqueID: "front-printing", BackUniqueID: "other-back-printing"},
+	}
+	store.indexPrintings()
+	store.indexFaces()
+	store.indexOrdinals()
+
+	faces := store.GetCardFaces("back")
+	if len(faces.Faces) != 3 || len(faces.Links) != 2 {
+		t.Errorf("GetCardFaces(back) = %d faces and %d links, want 3 and 2", len(faces.Faces), len(faces.Links))
+	}
+
+	for _, id := range []string{"back", "front", "other-back"} {
+		if group := store.FaceGroupByCardID[id]; group != "front" {
+			t.Errorf("Face %s is grouped under %q, want front", id, group)
+		}
+	}
+
+	cards, total := store.SearchCards(CardFilter{CollapseFaces: true})
+	if total != 3 || cards[0].UniqueID != "front" || cards[1].UniqueID != "middle" || cards[2].UniqueID != "single" {
+		t.Errorf("Collapsed search returned %d cards, want front, middle and single", total)
+	}
+
+	// The front face stands for the group even when only a back face matches
+	cards, total = store.SearchCards(CardFilter{Name: "other-back", CollapseFaces: true})
+	if total != 1 || cards[0].UniqueID != "front" {
+		t.Errorf("Collapsed search for a back face returned %d cards, want front", total)
+	}
+
+	if _, total := store.SearchCards(CardFilter{}); total != 5 {
+		t.Errorf("Uncollapsed search returned %d cards, want 5", total)
+	}
+
+	// A front face standing for a later back face keeps its own place, so
+	// cursors page through every card
+	filter := CardFilter{Values: []ValueFilter{{Attribute: AttributeType, Values: []string{"zap"}}}, CollapseFaces: true, Limit: 1}
+	for _, id := range []string{"middle", "other-back", "single"} {
+		store.CardsByID[id].Types = []string{"Zap"}
+	}
+	var paged []string
+	for {
+		page, err := store.SearchCardsPage(filter, nil)
+		if err != nil {
+			t.Fatalf("SearchCardsPage() error = %v", err)
+		}
+		for _, card := range page.Cards {
+			paged = append(paged, card.UniqueID)
+		}
+		if page.Next == "" || len(paged) > 3 {
+			break
+		}
+		filter.Cursor = page.Next
+	}
+	if want := []string{"front", "middle", "single"}; !slices.Equal(paged, want) {
+		t.Errorf("Collapsed pages = %v, want %v", paged, want)
+	}
+}
+
+func TestSearchPrintings(t *testing.T) {
+	store := &Store{
+		PrintingsByUniqueID: make(map[string]*domain.CardPrinting),
+		PrintingsByID:       make(map[string][]*domain.CardPrinting),
+	}
+	card := &domain.Card{UniqueID: "card", Name: "Card", Printings: []domain.Printing{
+		{UniqueID: "p1", ID: "WTR001", SetID: "WTR", Edition: "A", Foiling: "S", Rarity: "T"},
+		{UniqueID: "p2", ID: "WTR001", SetID: "WTR", Edition: "U", Foiling: "R", Rarity: "T"},
+		{UniqueID: "p3", ID: "HER001", SetID: "HER", Edition: "N", Foiling: "S", Rarity: "P"},
+	}}
+	store.Cards = []*domain.Card{card}
+	store.indexPrintings()
+
+	if printings := store.GetPrintings("wtr001"); len(printings) != 2 {
+		t.Errorf("GetPrintings(wtr001) returned %d printings, want 2", len(printings))
+	}
+	if printings := store.GetPrintings("p3"); len(printings) != 1 || printings[0].Card != card {
+		t.Error("GetPrintings(p3) should return the printing with its card")
+	}
+	if got := store.GetCardByPrintingID("WTR001"); got != card {
+		t.Error("GetCardByPrintingID(WTR001) should return the parent card")
+	}
+
+	printings, total := store.SearchPrintings(PrintingFilter{SetID: "wtr", Foiling: "s"})
+	if total != 1 || printings[0].UniqueID != "p1" {
+		t.Errorf("SearchPrintings(set=wtr, foiling=s) returned %d printings, want p1", total)
+	}
+	if _, total := store.SearchPrintings(PrintingFilter{Limit: 1}); total != 3 {
+		t.Errorf("SearchPrintings total = %d, want 3", total)
+	}
+}
+
+func TestDescribePrinting(t *testing.T) {
+	store := &Store{
+		SetsByID:          map[string]*domain.Set{"WTR": {ID: "WTR", Name: "Welcome to Rathe"}},
+		RaritiesByID:      make(map[string]*domain.Rarity),
+		FoilingsByID:      make(map[string]*domain.Foiling),
+		EditionsByID:      make(map[string]*domain.Edition),
+		ArtVariationsByID: make(map[string]*domain.ArtVariation),
+	}
+	if err := store.loadCodes(); err != nil {
+		t.Fatalf("loadCodes() error = %v", err)
+	}
+	if rarity := store.RaritiesByID["R"]; rarity == nil || rarity.Name != "Rare" {
+		t.Errorf("Rarity R = %+v, want Rare", rarity)
+	}
+
+	printing := &domain.CardPrinting{Printing: &domain.Printing{
+		SetID: "WTR", Edition: "U", Foiling: "C", Rarity: "L", ArtVariations: []string{"AA", "??"},
+	}}
+	described := store.DescribePrinting(printing)
+	if printing.Names != nil {
+		t.Error("DescribePrinting should not modify the indexed printing")
+	}
+
+	want := domain.PrintingNames{
+		Set: "Welcome to Rathe", Edition: "Unlimited", Foiling: "Cold Foil", Rarity: "Legendary",
+		ArtVariations: []string{"Alternate Art"},
+	}
+	got := described.Names
+	if got.Set != want.Set || got.Edition != want.Edition || got.Foiling != want.Foiling ||
+		got.Rarity != want.Rarity || !slices.Equal(got.ArtVariations, want.ArtVariations) {
+		t.Errorf("DescribePrinting names = %+v, want %+v", *got, want)
+	}
+}
+
+func TestArtistIndex(t *testing.T) {
+	store := &Store{
+		SetsByID:            map[string]*domain.Set{"WTR": {ID: "WTR", Name: "Welcome to Rathe"}},
+		ArtistsByName:       make(map[string]*domain.Artist),
+		PrintingsByArtist:   make(map[string][]*domain.CardPrinting),
+		PrintingsByUniqueID: make(map[string]*domain.CardPrinting),
+		PrintingsByID:       make(map[string][]*domain.CardPrinting),
+	}
+	if err := store.loadArtists(); err != nil {
+		t.Fatalf("loadArtists() error = %v", err)
+	}
+	loaded := len(store.Artists)
+	if loaded == 0 {
+		t.Fatal("Expected artists to be loaded")
+	}
+
+	store.Cards = []*domain.Card{
+		{UniqueID: "a", Printings: []domain.Printing{
+			{UniqueID: "a1", SetID: "WTR", Artists: []string{"Someone New"}},
+			{UniqueID: "a2", SetID: "1HP", Artists: []string{"Someone New"}},
+		}},
+		{UniqueID: "b", Printings: []domain.Printing{
+			{UniqueID: "b1", SetID: "WTR", Artists: []string{"someone new", "Another Artist"}},
+		}},
+	}
+	store.indexPrintings()
+	store.indexArtists()
+
+	if len(store.Artists) != loaded+2 {
+		t.Errorf("Expected artists missing from artist.json to be added, got %d artists", len(store.Artists))
+	}
+
+	artist := store.GetArtist("SOMEONE NEW")
+	if artist == nil {
+		t.Fatal("GetArtist should be case-insensitive")
+	}
+	if artist.PrintingCount != 3 || artist.CardCount != 2 {
+		t.Errorf("Artist has %d printings and %d cards, want 3 and 2", artist.PrintingCount, artist.CardCount)
+	}
+	wantSets := []domain.ArtistSetCount{{SetID: "1HP", Printings: 1}, {SetID: "WTR", SetName: "Welcome to Rathe", Printings: 2}}
+	if !slices.Equal(artist.Sets, wantSets) {
+		t.Errorf("Artist sets = %+v, want %+v", artist.Sets, wantSets)
+	}
+
+	printings, total := store.GetArtistPrintings("Someone New", ArtistPrintingFilter{SetID: "wtr", Limit: 1})
+	if total != 2 || len(printings) != 1 {
+		t.Errorf("GetArtistPrintings(set=wtr, limit=1) = %d of %d, want 1 of 2", len(printings), total)
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	store := &Store{}
+	if err := store.loadIcons(); err != nil {
+		t.Fatalf("loadIcons() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		markdown string
+		plain    string
+		mode     render.Mode
+		want     string
+	}{
+		{"raw", "**Go again**", "+1{p}", render.ModeRaw, "+1{p}"},
+		{"plain", "", "Gain {r}{r}. This gets +1{p} and {d}.", render.ModePlain, "Gain 2 resources. This gets +1 power and defense."},
+		{"plain unknown", "", "Pay {x}", render.ModePlain, "Pay {x}"},
+		{"markdown", "**Go again**\n{h}", "", render.ModeMarkdown, "**Go again**\nlife"},
+		{
+			"html", "**Go again** <b>{I}</b>", "", render.ModeHTML,
+			`<strong>Go again</strong> &lt;b&gt;<span class="icon icon-I" role="img" aria-label="intellect" title="Intellect (of a hero card)">{I}</span>&lt;/b&gt;`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := store.RenderText(tt.markdown, tt.plain, tt.mode); got != tt.want {
+				t.Errorf("RenderText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := render.ParseMode("bold"); err == nil {
+		t.Error("ParseMode(bold) should fail")
+	}
+}
+
+// writeDataDir copies the embedded data files into a temporary directory, with
+// the given card.json, for tests that load a store from disk.
+func writeDataDir(t *testing.T, cardJSON string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files, err := fs.ReadDir(embeddedFS(), ".")
+	if err != nil {
+		t.Fatalf("reading embedded data: %v", err)
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(embeddedFS(), file.Name())
+		if err != nil {
+			t.Fatalf("reading %s: %v", file.Name(), err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, file.Name()), data, 0o644); err != nil {
+			t.Fatalf("writing %s: %v", file.Name(), err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "card.json"), []byte(cardJSON), 0o644); err != nil {
+		t.Fatalf("writing card.json: %v", err)
+	}
+
+	return dir
+}
+
+func TestProviderReload(t *testing.T) {
+	dir := writeDataDir(t, `[{"unique_id": "a", "name": "First"}]`)
+
+	provider, err := NewProvider(ProviderConfig{Dir: dir}, nil)
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	before := provider.Store()
+	if before.GetCardByID("a") == nil || before.Version == "" {
+		t.Fatalf("Expected the store to be loaded from %s with a version", dir)
+	}
+
+	cards := `[{"unique_id": "a", "name": "First"}, {"unique_id": "b", "name": "Second"}]`
+	if err := os.WriteFile(filepath.Join(dir, "card.json"), []byte(cards), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := provider.Reload()
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if provider.Store() != after || after.GetCardByID("b") == nil {
+		t.Error("Expected the reloaded store to be swapped in")
+	}
+	if after.Version == before.Version {
+		t.Error("Expected the data version to change with the data")
+	}
+	if before.GetCardByID("b") != nil {
+		t.Error("The previous store should be left untouched")
+	}
+	if changelog := provider.Changelog(); changelog == nil || changelog.Summary.Added != 1 || changelog.FromVersion != before.Version {
+		t.Errorf("Expected a changelog from %s with one added card, got %+v", before.Version, changelog)
+	}
+
+	// A failed reload keeps the current store
+	if err := os.WriteFile(filepath.Join(dir, "card.json"), []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.Reload(); err == nil {
+		t.Error("Expected Reload() to fail on invalid data")
+	}
+	if provider.Store() != after {
+		t.Error("Expected the current store to be kept after a failed reload")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	store := &Store{
+		CardsByID:           make(map[string]*domain.Card),
+		SetsByID:            map[string]*domain.Set{"WTR": {ID: "WTR"}},
+		TypesByName:         map[string]*domain.Type{"Action": {Name: "Action"}},
+		PrintingsByUniqueID: make(map[string]*domain.CardPrinting),
+		PrintingsByID:       make(map[string][]*domain.CardPrinting),
+		RaritiesByID:        map[string]*domain.Rarity{"C": {ID: "C"}},
+		FoilingsByID:        map[string]*domain.Foiling{"S": {ID: "S"}},
+		EditionsByID:        map[string]*domain.Edition{"A": {ID: "A"}},
+		ArtVariationsByID:   make(map[string]*domain.ArtVariation),
+	}
+	valid := domain.Printing{UniqueID: "p1", SetID: "WTR", Edition: "A", Foiling: "S", Rarity: "C"}
+	store.Cards = []*domain.Card{
+		{UniqueID: "a", Name: "A", Types: []string{"Action"}, Printings: []domain.Printing{valid}},
+		{UniqueID: "a", Name: "Duplicate", Types: []string{"Nope"}, Printings: []domain.Printing{
+			{UniqueID: "p2", SetID: "XXX", Edition: "A", Foiling: "Q", Rarity: "C", ArtVariations: []string{"ZZ"}},
+		}},
+	}
+	for _, card := range store.Cards {
+		store.CardsByID[card.UniqueID] = card
+	}
+	store.indexPrintings()
+	store.LegalityEvents = []*domain.LegalityEvent{
+		{UniqueID: "e1", CardUniqueID: "missing", Format: domain.FormatBlitz, Status: domain.StatusBanned},
+	}
+	store.CardReferences = []*domain.CardReference{{CardUniqueID: "a", ReferencedCardUniqueID: "gone"}}
+	store.FaceAssociations = []*domain.FaceAssociation{{FrontUniqueID: "p1", BackUniqueID: "nowhere"}}
+
+	issues := store.Validate()
+
+	want := []struct {
+		code  IssueCode
+		file  string
+		field string
+	}{
+		{IssueDuplicateID, "card.json", "unique_id"},
+		{IssueUnknownCode, "card.json", "types"},
+		{IssueBrokenReference, "card.json", "printings[0].set_id"},
+		{IssueUnknownCode, "card.json", "printings[0].foiling"},
+		{IssueUnknownCode, "card.json", "printings[0].art_variations"},
+		{IssueBrokenReference, "banned-blitz.json", "card_unique_id"},
+		{IssueBrokenReference, "card-reference.json", "referenced_card_unique_id"},
+		{IssueBrokenReference, "card-face-association.json", "back_unique_id"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("Validate() found %d issues, want %d: %v", len(issues), len(want), issues)
+	}
+	for i, w := range want {
+		if issues[i].Code != w.code || issues[i].File != w.file || issues[i].Field != w.field {
+			t.Errorf("Issue %d = %s [%s], want %s %s [%s]", i, issues[i], issues[i].Code, w.file, w.field, w.code)
+		}
+	}
+}
+
+func TestProviderStrict(t *testing.T) {
+	// Ban records reference cards missing from this card.json
+	dir := writeDataDir(t, `[]`)
+
+	if _, err := NewProvider(ProviderConfig{Dir: dir}, nil); err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	_, err := NewProvider(ProviderConfig{Dir: dir, Strict: true}, nil)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Issues) == 0 {
+		t.Errorf("Strict NewProvider() error = %v, want a ValidationError", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	newStore := func(version string, cards ...*domain.Card) *Store {
+		store := &Store{Version: version, Cards: cards, CardsByID: make(map[string]*domain.Card)}
+		for _, card := range cards {
+			store.CardsByID[card.UniqueID] = card
+		}
+		return store
+	}
+
+	from := newStore("v1",
+		&domain.Card{UniqueID: "kept", Name: "Kept", Power: "3", BlitzLegal: true},
+		&domain.Card{UniqueID: "same", Name: "Same"},
+		&domain.Card{UniqueID: "gone", Name: "Gone"},
+	)
+	to := newStore("v2",
+		&domain.Card{UniqueID: "kept", Name: "Kept", Power: "4", FunctionalTextPlain: "**Go again**", BlitzLegal: true, BlitzBanned: true},
+		&domain.Card{UniqueID: "same", Name: "Same"},
+		&domain.Card{UniqueID: "new", Name: "New"},
+	)
+
+	changelog := Diff(from, to)
+
+	if changelog.FromVersion != "v1" || changelog.ToVersion != "v2" {
+		t.Errorf("Changelog versions = %s -> %s, want v1 -> v2", changelog.FromVersion, changelog.ToVersion)
+	}
+	if len(changelog.Added) != 1 || changelog.Added[0].UniqueID != "new" {
+		t.Errorf("Added = %+v, want new", changelog.Added)
+	}
+	if len(changelog.Removed) != 1 || changelog.Removed[0].UniqueID != "gone" {
+		t.Errorf("Removed = %+v, want gone", changelog.Removed)
+	}
+	if len(changelog.Changed) != 1 || changelog.Changed[0].UniqueID != "kept" {
+		t.Fatalf("Changed = %+v, want kept", changelog.Changed)
+	}
+
+	want := []domain.FieldChange{
+		{Field: "functional_text", Kind: domain.ChangeText, Before: "", After: "**Go again**"},
+		{Field: "power", Kind: domain.ChangeStats, Before: "3", After: "4"},
+		{Field: "legality.blitz", Kind: domain.ChangeLegality, Before: "legal", After: "banned"},
+	}
+	if !slices.Equal(changelog.Changed[0].Changes, want) {
+		t.Errorf("Changes = %+v, want %+v", changelog.Changed[0].Changes, want)
+	}
+
+	summary := changelog.Summary
+	if summary.Text != 1 || summary.Stats != 1 || summary.Legality != 1 {
+		t.Errorf("Summary = %+v, want one change of each kind", summary)
+	}
+}
+
+func TestTextIndex(t *testing.T) {
+	cards := []*domain.Card{
+		{UniqueID: "a", Name: "Snatch", TypeText: "Generic Action - Attack", Types: []string{"Generic", "Action", "Attack"}, FunctionalTextPlain: "If Snatch hits, draw a card."},
+		{UniqueID: "b", Name: "Sink Below", TypeText: "Generic Defense Reaction", FunctionalTextPlain: "You may put a card from your hand on the bottom of your deck. If you do, draw a card."},
+		{UniqueID: "c", Name: "Drawn Blade", TypeText: "Warrior Action", FunctionalTextPlain: "Go again"},
+		{UniqueID: "d", Name: "Razor Reflex", TypeText: "Generic Attack Reaction", Types: []string{"Generic", "Attack Reaction"}, FunctionalTextPlain: "Target attack with go again gets +1 power. Attacks hit harder."},
+		{UniqueID: "e", Name: "Nullrune Hood", TypeText: "Generic Equipment - Head", FunctionalTextPlain: "Your hero loses all abilities."},
+	}
+	store := &Store{Cards: cards, TextIndex: NewTextIndex(cards)}
+
+	ids := func(query string) []string {
+		var got []string
+		for _, hit := range store.TextIndex.Search(query) {
+			got = append(got, hit.Card.UniqueID)
+		}
+		return got
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"snatch", []string{"a"}},
+		{"draw card", []string{"a", "b"}},
+		{`"draw a card"`, []string{"a", "b"}},
+		{`"card draw"`, nil},
+		{"draw*", []string{"c", "a", "b"}},
+		{"attacks", []string{"d", "a"}},
+		{"attack*", []string{"d", "a"}},
+		{"attacks*", []string{"d", "a"}},
+		{"abilit*", []string{"e"}},
+		{"abilities*", []string{"e"}},
+		{`"go again" target`, []string{"d"}},
+		{"!!", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := ids(tt.query); !slices.Equal(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	scored, total := store.SearchCardsScored(CardFilter{TextQuery: "attack", Class: "Generic", Limit: 1})
+	if total != 2 || len(scored) != 1 || scored[0].Score == 0 {
+		t.Errorf("SearchCardsScored() = %+v, %d, want the best of 2 scored matches", scored, total)
+	}
+
+	// A text query without words leaves the other filters to do the search
+	if _, total := store.SearchCards(CardFilter{TextQuery: "!!", Class: "Generic"}); total != 2 {
+		t.Errorf("SearchCards() with a text query of punctuation = %d cards, want the 2 generic ones", total)
+	}
+}
+
+func TestQueryCards(t *testing.T) {
+	cards := []*domain.Card{
+		{
+			UniqueID: "a", Name: "Lunging Press", Pitch: "1", Power: "4", Types: []string{"Ninja", "Action", "Attack"},
+			TypeText: "Ninja Action - Attack", CardKeywords: []string{"Go again"}, CCLegal: true, BlitzLegal: true,
+			Printings: []domain.Printing{{SetID: "WTR", Rarity: "C"}},
+		},
+		{
+			UniqueID: "b", Name: "Mugenshi: RELEASE", Pitch: "1", Power: "5", Types: []string{"Ninja", "Action", "Attack"},
+			TypeText: "Ninja Action - Attack", CCLegal: true, BlitzBanned: true,
+			Printings: []domain.Printing{{SetID: "WTR", Rarity: "M"}},
+		},
+		{
+			UniqueID: "c", Name: "Tectonic Plating", Defense: "2", Types: []string{"Guardian", "Equipment", "Chest"},
+			TypeText: "Guardian Equipment - Chest", CCLegal: true, BlitzLegal: true,
+			Printings: []domain.Printing{{SetID: "ARC", Rarity: "R"}},
+		},
+		{
+			UniqueID: "d", Name: "Command and Conquer", Pitch: "1", Power: "X", Types: []string{"Generic", "Action", "Attack"},
+			TypeText: "Generic Action - Attack", CCLegal: true,
+			Printings: []domain.Printing{{SetID: "ARC", Rarity: "M"}},
+		},
+	}
+	for _, card := range cards {
+		card.ParseStats()
+	}
+	rarities := map[string]*domain.Rarity{"M": {ID: "M", Name: "Majestic"}}
+	scan := &Store{Cards: cards, RaritiesByID: rarities}
+	indexed := &Store{Cards: cards, RaritiesByID: rarities, Bitmaps: NewBitmapIndex(cards)}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`c:ninja t:attack pow>=4 kw:"go again" -legal:blitz`, nil},
+		{`c:ninja t:attack pow>=4 kw:"go again" legal:blitz`, []string{"a"}},
+		{`c:ninja pow>4`, []string{"b"}},
+		{`t=equipment or banned:blitz`, []string{"b", "c"}},
+		{`(s:wtr OR s:arc) -(c:ninja and p=1)`, []string{"c", "d"}},
+		{`not t:attack`, []string{"c"}},
+		{`r:majestic`, []string{"b", "d"}},
+		{`pow!=4`, []string{"b"}},
+		{`"command and"`, []string{"d"}},
+		{`press`, []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, err := query.Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			for _, store := range []*Store{scan, indexed} {
+				var got []string
+				found, _ := store.SearchCards(CardFilter{Query: expr})
+				for _, card := range found {
+					got = append(got, card.UniqueID)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("SearchCards(%s) = %v, want %v (indexed: %t)", expr, got, tt.want, store.Bitmaps != nil)
+				}
+			}
+		})
+	}
+
+	errorTests := []struct {
+		query string
+		pos   int
+		token string
+	}{
+		{`c:ninja zz:top`, 8, "zz:top"},
+		{`pow>=four`, 5, "four"},
+		{`c>ninja`, 0, "c>ninja"},
+		{`(c:ninja or t:attack`, 0, "("},
+		{`c:ninja)`, 7, ")"},
+		{`kw:"go again`, 3, `"go again`},
+		{`legal:vintage`, 6, "vintage"},
+		{`c:ninja or`, 10, ""},
+		{``, 0, ""},
+	}
+
+	for _, tt := range errorTests {
+		t.Run("error "+tt.query, func(t *testing.T) {
+			_, err := query.Parse(tt.query)
+			var syntaxErr *query.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse() error = %v, want a syntax error", err)
+			}
+			if syntaxErr.Pos != tt.pos || syntaxErr.Token != tt.token {
+				t.Errorf("Parse() error at %d %q, want %d %q (%v)", syntaxErr.Pos, syntaxErr.Token, tt.pos, tt.token, err)
+			}
+		})
+	}
+
+	// Classes match any of a card's classes, as class filters do
+	multi := []*domain.Card{{UniqueID: "ninja-warrior", Types: []string{"Ninja", "Warrior", "Action"}}}
+	expr, err := query.Parse(`c=warrior`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	for _, store := range []*Store{{Cards: multi}, {Cards: multi, Bitmaps: NewBitmapIndex(multi)}} {
+		if _, total := store.SearchCards(CardFilter{Query: expr}); total != 1 {
+			t.Errorf("SearchCards(c=warrior) matched %d cards, want the Ninja Warrior (indexed: %t)", total, store.Bitmaps != nil)
+		}
+	}
+}
+
+func TestStatRanges(t *testing.T) {
+	cards := []*domain.Card{
+		{UniqueID: "a", Cost: "0", Power: "3"},
+		{UniqueID: "b", Cost: "2", Power: "6"},
+		{UniqueID: "c", Cost: "X", Power: "*"},
+		{UniqueID: "d", Defense: "2"},
+	}
+	for _, card := range cards {
+		card.ParseStats()
+	}
+	store := &Store{Cards: cards}
+
+	if stat := cards[2].Stats.Cost; stat == nil || !stat.Variable {
+		t.Errorf("Stats.Cost = %+v, want a variable stat", stat)
+	}
+	if cards[3].Stats.Cost != nil {
+		t.Errorf("Stats.Cost = %+v, want nil for a card without a cost", cards[3].Stats.Cost)
+	}
+
+	type bound struct {
+		stat   domain.StatName
+		suffix string
+		value  int
+	}
+	tests := []struct {
+		name            string
+		bounds          []bound
+		includeVariable bool
+		want            []string
+	}{
+		{"min", []bound{{domain.StatCost, "min", 1}}, false, []string{"b"}},
+		{"max", []bound{{domain.StatCost, "max", 1}}, false, []string{"a"}},
+		{"gt and lt", []bound{{domain.StatPower, "gt", 3}, {domain.StatPower, "lt", 7}}, false, []string{"b"}},
+		{"narrowest", []bound{{domain.StatCost, "gte", 0}, {domain.StatCost, "min", 2}}, false, []string{"b"}},
+		{"include variable", []bound{{domain.StatCost, "min", 1}}, true, []string{"b", "c"}},
+		{"empty range", []bound{{domain.StatDefense, "gte", 3}, {domain.StatDefense, "lte", 2}}, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := CardFilter{IncludeVariable: tt.includeVariable}
+			for _, b := range tt.bounds {
+				if !filter.SetStatBound(b.stat, b.suffix, b.value) {
+					t.Fatalf("SetStatBound(%s, %s) = false", b.stat, b.suffix)
+				}
+			}
+
+			var got []string
+			found, _ := store.SearchCards(filter)
+			for _, card := range found {
+				got = append(got, card.UniqueID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SearchCards() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if (&CardFilter{}).SetStatBound(domain.StatCost, "above", 1) {
+		t.Error("SetStatBound(above) = true, want false")
+	}
+}
+
+func TestNameIndex(t *testing.T) {
+	cards := []*domain.Card{
+		{UniqueID: "a", Name: "Command and Conquer", Pitch: "1"},
+		{UniqueID: "b", Name: "Command and Conquer", Pitch: "2"},
+		{UniqueID: "c", Name: "Oldhim's Ötzi-Spear"},
+		{UniqueID: "d", Name: "Commanding Strike"},
+		{UniqueID: "e", Name: "Snatch"},
+		{UniqueID: "f", Name: "Scratch"},
+	}
+	store := &Store{Cards: cards, CardsByName: map[string][]*domain.Card{}, Names: NewNameIndex(cards)}
+
+	if got := normalizeName("Oldhim's Ötzi-Spear"); got != "oldhims otzi spear" {
+		t.Errorf("normalizeName() = %q", got)
+	}
+
+	if got := store.AutocompleteCardNames("comm", 10); !slices.Equal(got, []string{"Command and Conquer", "Commanding Strike"}) {
+		t.Errorf("AutocompleteCardNames(comm) = %v", got)
+	}
+	if got := store.AutocompleteCardNames("otzi", 10); !slices.Equal(got, []string{"Oldhim's Ötzi-Spear"}) {
+		t.Errorf("AutocompleteCardNames(otzi) = %v", got)
+	}
+	if got := store.AutocompleteCardNames("comm", 1); len(got) != 1 {
+		t.Errorf("AutocompleteCardNames(comm, 1) = %v, want 1 name", got)
+	}
+
+	tests := []struct {
+		name  string
+		want  []string
+		fuzzy bool
+	}{
+		{"comand and conqer", []string{"a", "b"}, true},
+		{"oldhims otzi spear", []string{"c"}, true},
+		{"Comand And Conquer", []string{"a", "b"}, true},
+		{"snatch!", []string{"e"}, true},
+		{"Scrach", []string{"f"}, true},
+		{"Snatc", []string{"e"}, true},
+		{"Scatch", nil, false}, // as close to Snatch as to Scratch
+		{"Lightning Press", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, fuzzy := store.FindCardsByName(tt.name)
+			var got []string
+			for _, card := range found {
+				got = append(got, card.UniqueID)
+			}
+			if !slices.Equal(got, tt.want) || fuzzy != tt.fuzzy {
+				t.Errorf("FindCardsByName() = %v, %v, want %v, %v", got, fuzzy, tt.want, tt.fuzzy)
+			}
+		})
+	}
+
+	if got := store.SuggestCardNames("Scatch", 5); !slices.Equal(got, []string{"Scratch", "Snatch"}) {
+		t.Errorf("SuggestCardNames(Scatch) = %v", got)
+	}
+
+	// The name filter forgives punctuation and diacritics too
+	if found, _ := store.SearchCards(CardFilter{Name: "otzi spear"}); len(found) != 1 {
+		t.Errorf("SearchCards(name) = %d cards, want 1", len(found))
+	}
+}
+
+func TestResolveCard(t *testing.T) {
+	cards := []*domain.Card{
+		{UniqueID: "red", Name: "Snatch", Pitch: "1", Color: "Red"},
+		{UniqueID: "yellow", Name: "Snatch", Pitch: "2", Color: "Yellow"},
+		{UniqueID: "blue", Name: "Snatch", Pitch: "3", Color: "Blue"},
+		{UniqueID: "front", Name: "Blossom", Pitch: "1"},
+		{UniqueID: "back", Name: "Blossom", Pitch: "1"},
+		{UniqueID: "hero", Name: "Dorinthea"},
+	}
+	store := &Store{
+		Cards:             cards,
+		CardsByID:         map[string]*domain.Card{},
+		CardsByName:       map[string][]*domain.Card{},
+		FaceGroupByCardID: map[string]string{"front": "front", "back": "front"},
+		Names:             NewNameIndex(cards),
+	}
+	for _, card := range cards {
+		store.CardsByID[card.UniqueID] = card
+		key := strings.ToLower(card.Name)
+		store.CardsByName[key] = append(store.CardsByName[key], card)
+	}
+
+	tests := []struct {
+		name         string
+		id           string
+		pitch, color string
+		want         string
+		candidates   int
+	}{
+		{"by ID", "blue", "", "", "blue", 0},
+		{"ambiguous", "snatch", "", "", "", 3},
+		{"by pitch", "snatch", "2", "", "yellow", 0},
+		{"by color", "Snatch", "", "blue", "blue", 0},
+		{"no such variant", "snatch", "2", "red", "", 0},
+		{"faces of one card", "blossom", "", "", "front", 0},
+		{"unique name", "dorinthea", "", "", "hero", 0},
+		{"misspelled", "snach", "1", "", "red", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := store.ResolveCard(tt.id, tt.pitch, tt.color)
+			var got string
+			if match.Card != nil {
+				got = match.Card.UniqueID
+			}
+			if got != tt.want || len(match.Candidates) != tt.candidates {
+				t.Errorf("ResolveCard() = %q with %d candidates, want %q with %d", got, len(match.Candidates), tt.want, tt.candidates)
+			}
+		})
+	}
+}
+
+func TestSortResults(t *testing.T) {
+	cards := []*domain.Card{
+		{UniqueID: "a", Name: "Snatch", Pitch: "1", Cost: "0", Power: "4"},
+		{UniqueID: "b", Name: "Snatch", Pitch: "3", Cost: "0", Power: "2"},
+		{UniqueID: "c", Name: "Anothos", Cost: "X"},
+		{UniqueID: "d", Name: "Bone Head Barrier", Pitch: "2", Cost: "2", Defense: "3"},
+		{UniqueID: "e", Name: "ancestral Empowerment", Pitch: "1", Cost: "0", Power: "4"},
+	}
+	for _, card := range cards {
+		card.ParseStats()
+	}
+	released := func(date string) time.Time {
+		t, _ := time.Parse(time.DateOnly, date)
+		return t
+	}
+	store := &Store{
+		Cards: cards,
+		ReleaseDateByCardID: map[string]time.Time{
+			"a": released("2019-10-11"), "b": released("2019-10-11"), "d": released("2020-03-27"), "e": released("2021-01-29"),
+		},
+	}
+
+	tests := []struct {
+		sort  CardSort
+		order SortOrder
+		want  []string
+	}{
+		{CardSortDefault, OrderDefault, []string{"a", "b", "c", "d", "e"}},
+		{CardSortName, OrderDefault, []string{"e", "c", "d", "a", "b"}},
+		{CardSortName, OrderDescending, []string{"a", "b", "d", "c", "e"}},
+		{CardSortCost, OrderAscending, []string{"e", "a", "b", "d", "c"}},
+		{CardSortCost, OrderDescending, []string{"d", "e", "a", "b", "c"}},
+		{CardSortPower, OrderDescending, []string{"e", "a", "b", "c", "d"}},
+		{CardSortPitch, OrderDefault, []string{"e", "a", "d", "b", "c"}},
+		{CardSortReleaseDate, OrderDescending, []string{"e", "d", "a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.sort)+" "+string(tt.order), func(t *testing.T) {
+			var got []string
+			found, _ := store.SearchCards(CardFilter{Sort: tt.sort, Order: tt.order})
+			for _, card := range found {
+				got = append(got, card.UniqueID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SearchCards() = %v, want %v", got, tt.want)
+			}
+
+			// Pages of a sorted search never overlap
+			first, _ := store.SearchCards(CardFilter{Sort: tt.sort, Order: tt.order, Limit: 2})
+			rest, _ := store.SearchCards(CardFilter{Sort: tt.sort, Order: tt.order, Offset: 2})
+			for _, card := range first {
+				if slices.Contains(rest, card) {
+					t.Errorf("card %s appears on two pages", card.UniqueID)
+				}
+			}
+		})
+	}
+
+	if _, err := ParseCardSort("color"); err == nil {
+		t.Error("ParseCardSort(color) should fail")
+	}
+	if _, err := ParseSortOrder("up"); err == nil {
+		t.Error("ParseSortOrder(up) should fail")
+	}
+
+	// The default sorts ignore the order, so it is only taken with a sort
+	if _, err := ParseCardOrder(CardSortDefault, "desc"); err == nil {
+		t.Error("ParseCardOrder(desc) without a sort should fail")
+	}
+	if order, err := ParseCardOrder(CardSortName, "desc"); err != nil || order != OrderDescending {
+		t.Errorf("ParseCardOrder(name, desc) = %q, %v, want desc", order, err)
+	}
+	if _, err := ParseSetOrder(SetSortDefault, "asc"); err == nil {
+		t.Error("ParseSetOrder(asc) without a sort should fail")
+	}
+
+	sets := &Store{Sets: []*domain.Set{
+		{ID: "MON", Name: "Monarch", Printings: []domain.SetPrinting{{InitialReleaseDate: "2021-05-07T00:00:00.000Z"}}},
+		{ID: "WTR", Name: "Welcome to Rathe", Printings: []domain.SetPrinting{
+			{InitialReleaseDate: "2020-11-06T00:00:00.000Z"}, {InitialReleaseDate: "2019-10-11T00:00:00.000Z"},
+		}},
+		{ID: "ARC", Name: "Arcane Rising", Printings: []domain.SetPrinting{{InitialReleaseDate: "2020-03-27T00:00:00.000Z"}}},
+	}}
+	var got []string
+	for _, set := range sets.SearchSets(SetFilter{Sort: SetSortReleaseDate}) {
+		got = append(got, set.ID)
+	}
+	if !slices.Equal(got, []string{"WTR", "ARC", "MON"}) {
+		t.Errorf("SearchSets(release_date) = %v", got)
+	}
+}
+
+func TestValueFilters(t *testing.T) {
+	printing := func(set string) []domain.Printing { return []domain.Printing{{SetID: set}, {SetID: set}} }
+	cards := []*domain.Card{
+		{UniqueID: "a", Types: []string{"Ninja", "Action", "Attack"}, CardKeywords: []string{"Go again"}, Pitch: "1", Printings: printing("WTR")},
+		{UniqueID: "b", Types: []string{"Warrior", "Equipment"}, CardKeywords: []string{"Dominate", "Go again"}, Printings: printing("WTR")},
+		{UniqueID: "c", Types: []string{"Ninja", "Warrior", "Action", "Attack"}, CardKeywords: []string{"Dominate"}, Pitch: "2", Printings: printing("ARC")},
+		{UniqueID: "d", Types: []string{"Wizard", "Action"}, CardKeywords: []string{"Go again", "Dominate"}, Pitch: "3", Printings: printing("ARC")},
+		{UniqueID: "e", Types: []string{"Generic", "Action"}, CardKeywords: []string{"Go again"}, Pitch: "1", Printings: printing("MON")},
+	}
+	// Every case runs with a full scan and with the bitmap index
+	scan := &Store{Cards: cards}
+	indexed := &Store{Cards: cards, Bitmaps: NewBitmapIndex(cards)}
+
+	tests := []struct {
+		name   string
+		filter CardFilter
+		want   []string
+	}{
+		{"any class", CardFilter{Values: []ValueFilter{{Attribute: AttributeClass, Values: []string{"ninja", "Warrior"}}}}, []string{"a", "b", "c"}},
+		{"all classes", CardFilter{Values: []ValueFilter{{Attribute: AttributeClass, Values: []string{"Ninja", "Warrior"}, Mode: MatchAll}}}, []string{"c"}},
+		{"any keyword", CardFilter{Values: []ValueFilter{{Attribute: AttributeKeyword, Values: []string{"dom", "again"}}}}, []string{"a", "b", "c", "d", "e"}},
+		{"all keywords", CardFilter{Values: []ValueFilter{{Attribute: AttributeKeyword, Values: []string{"Go again", "Dominate"}, Mode: MatchAll}}}, []string{"b", "d"}},
+		{"partial keyword in several buckets", CardFilter{Keyword: "a"}, []string{"a", "b", "c", "d", "e"}},
+		{"set listed once per card", CardFilter{SetID: "wtr"}, []string{"a", "b"}},
+		{"any set", CardFilter{Values: []ValueFilter{{Attribute: AttributeSet, Values: []string{"MON", "WTR"}}}}, []string{"a", "b", "e"}},
+		{"any pitch", CardFilter{Values: []ValueFilter{{Attribute: AttributePitch, Values: []string{"1", "3"}}}}, []string{"a", "d", "e"}},
+		{"negated type", CardFilter{Values: []ValueFilter{{Attribute: AttributeType, Values: []string{"Equipment", "Attack"}, Negate: true}}}, []string{"d", "e"}},
+		{"combined", CardFilter{
+			Class: "Warrior",
+			Values: []ValueFilter{
+				{Attribute: AttributeKeyword, Values: []string{"Dominate"}},
+				{Attribute: AttributeType, Values: []string{"Equipment"}, Negate: true},
+			},
+		}, []string{"c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, store := range []*Store{scan, indexed} {
+				var got []string
+				found, total := store.SearchCards(tt.filter)
+				for _, card := range found {
+					got = append(got, card.UniqueID)
+				}
+				if !slices.Equal(got, tt.want) || total != len(tt.want) {
+					t.Errorf("SearchCards() = %v (total %d), want %v (indexed: %t)", got, total, tt.want, store.Bitmaps != nil)
+				}
+			}
+		})
+	}
+
+	if got := SplitValues(" Go again, ,Dominate "); !slices.Equal(got, []string{"Go again", "Dominate"}) {
+		t.Errorf("SplitValues() = %q", got)
+	}
+	if _, err := ParseMatchMode("some"); err == nil {
+		t.Error("ParseMatchMode(some) should fail")
+	}
+}
+
+func TestSearchCardsFaceted(t *testing.T) {
+	cards := []*domain.Card{
+		{UniqueID: "a", Types: []string{"Ninja", "Action", "Attack"}, Pitch: "1", Printings: []domain.Printing{{SetID: "WTR", Rarity: "C"}, {SetID: "WTR", Rarity: "C"}}},
+		{UniqueID: "b", Types: []string{"Ninja", "Warrior", "Action", "Attack"}, Pitch: "1", Printings: []domain.Printing{{SetID: "WTR", Rarity: "R"}, {SetID: "ARC", Rarity: "C"}}},
+		{UniqueID: "c", Types: []string{"Ninja", "Action"}, Pitch: "3", CardKeywords: []string{"Go again"}, Printings: []domain.Printing{{SetID: "ARC", Rarity: "M"}}},
+		{UniqueID: "d", Types: []string{"Wizard", "Action"}, Pitch: "2", Printings: []domain.Printing{{SetID: "MON", Rarity: "C"}}},
+	}
+	store := &Store{Cards: cards}
+
+	facets, err := ParseFacets("pitch,set, rarity,class,pitch")
+	if err != nil {
+		t.Fatalf("ParseFacets() error = %v", err)
+	}
+	if _, err := ParseFacets("color"); err == nil {
+		t.Error("ParseFacets(color) should fail")
+	}
+
+	// Counts cover every match, not only the page
+	found, total, counts := store.SearchCardsFaceted(CardFilter{Class: "Ninja", Limit: 1}, facets)
+	if len(found) != 1 || total != 3 {
+		t.Fatalf("SearchCardsFaceted() = %d cards, total %d, want 1 and 3", len(found), total)
+	}
+
+	want := FacetCounts{
+		FacetPitch:  {{"1", 2}, {"3", 1}},
+		FacetSet:    {{"ARC", 2}, {"WTR", 2}},
+		FacetRarity: {{"C", 2}, {"M", 1}, {"R", 1}},
+		FacetClass:  {{"Ninja", 3}, {"Warrior", 1}},
+	}
+	if len(counts) != len(want) {
+		t.Errorf("got %d facets, want %d", len(counts), len(want))
+	}
+	for facet, values := range want {
+		if !slices.Equal(counts[facet], values) {
+			t.Errorf("facet %s = %v, want %v", facet, counts[facet], values)
+		}
+	}
+
+	if _, _, counts := store.SearchCardsFaceted(CardFilter{}, nil); counts != nil {
+		t.Errorf("SearchCardsFaceted() without facets = %v, want nil", counts)
+	}
+}
+
+func TestCursorPagination(t *testing.T) {
+	cards := []*domain.Card{
+		{UniqueID: "a", Name: "Snatch", Pitch: "1", Cost: "0", FunctionalTextPlain: "Draw a card."},
+		{UniqueID: "b", Name: "Snatch", Pitch: "3", Cost: "0", FunctionalTextPlain: "Draw a card."},
+		{UniqueID: "c", Name: "Anothos", Cost: "X"},
+		{UniqueID: "d", Name: "Bone Head Barrier", Pitch: "2", Cost: "2", FunctionalTextPlain: "Draw a card, then draw a card."},
+		{UniqueID: "e", Name: "Ancestral Empowerment", Pitch: "1", Cost: "0"},
+		{UniqueID: "f", Name: "Command and Conquer", Pitch: "1", Cost: "2", FunctionalTextPlain: "Draw."},
+	}
+	for _, card := range cards {
+		card.ParseStats()
+	}
+	store := &Store{
+		Version:   "v1",
+		Cards:     cards,
+		Sets:      []*domain.Set{{ID: "WTR", Name: "Welcome to Rathe"}, {ID: "ARC", Name: "Arcane Rising"}, {ID: "CRU", Name: "Crucible of War"}},
+		TextIndex: NewTextIndex(cards),
+	}
+	store.indexOrdinals()
+
+	ids := func(cards []domain.ScoredCard) []string {
+		var got []string
+		for _, card := range cards {
+			got = append(got, card.UniqueID)
+		}
+		return got
+	}
+
+	filters := map[string]CardFilter{
+		"file order": {},
+		"name desc":  {Sort: CardSortName, Order: OrderDescending},
+		"cost":       {Sort: CardSortCost},
+		"relevance":  {TextQuery: "draw"},
+	}
+	for name, filter := range filters {
+		t.Run(name, func(t *testing.T) {
+			all, _ := store.SearchCardsScored(filter)
+			want := ids(all)
+
+			// Walk forward from the first page, then back from the last
+			var forward []string
+			var last CardPage
+			filter.Limit = 2
+			for page, err := store.SearchCardsPage(filter, nil); ; page, err = store.SearchCardsPage(filter, nil) {
+				if err != nil {
+					t.Fatalf("SearchCardsPage() error = %v", err)
+				}
+				forward = append(forward, ids(page.Cards)...)
+				last = page
+				if page.Next == "" {
+					break
+				}
+				filter.Cursor = page.Next
+			}
+			if !slices.Equal(forward, want) {
+				t.Errorf("forward pages = %v, want %v", forward, want)
+			}
+
+			backward := ids(last.Cards)
+			for page := last; page.Prev != ""; {
+				filter.Cursor = page.Prev
+				var err error
+				if page, err = store.SearchCardsPage(filter, nil); err != nil {
+					t.Fatalf("SearchCardsPage() error = %v", err)
+				}
+				backward = append(ids(page.Cards), backward...)
+			}
+			if !slices.Equal(backward, want) {
+				t.Errorf("backward pages = %v, want %v", backward, want)
+			}
+		})
+	}
+
+	// A cursor only resumes the search it was issued for
+	first, _ := store.SearchCardsPage(CardFilter{Sort: CardSortName, Limit: 2}, nil)
+	var cursorErr *CursorError
+	if _, err := store.SearchCardsPage(CardFilter{Sort: CardSortName, Name: "on", Limit: 2, Cursor: first.Next}, nil); !errors.As(err, &cursorErr) || cursorErr.Stale {
+		t.Errorf("cursor for another filter: error = %v, want a CursorError", err)
+	}
+	if page, err := store.SearchCardsPage(CardFilter{Sort: CardSortName, Limit: 3, Offset: 4, Cursor: first.Next}, []Facet{FacetPitch}); err != nil || page.Offset != 2 {
+		t.Errorf("cursor with another page size and facets: page at %d (%v), want 2", page.Offset, err)
+	}
+
+	if _, err := store.SearchCardsPage(CardFilter{Limit: 2, Cursor: first.Next}, nil); !errors.As(err, &cursorErr) || cursorErr.Stale {
+		t.Errorf("cursor for another sort: error = %v, want a CursorError", err)
+	}
+	if _, err := store.SearchCardsPage(CardFilter{Cursor: "not a cursor"}, nil); !errors.As(err, &cursorErr) || cursorErr.Stale {
+		t.Errorf("invalid cursor: error = %v, want a CursorError", err)
+	}
+
+	reloaded := *store
+	reloaded.Version = "v2"
+	if _, err := reloaded.SearchCardsPage(CardFilter{Sort: CardSortName, Cursor: first.Next}, nil); !errors.As(err, &cursorErr) || !cursorErr.Stale {
+		t.Errorf("stale cursor: error = %v, want a stale CursorError", err)
+	}
+
+	// Sets page by name, and in file order by default
+	sets, err := store.SearchSetsPage(SetFilter{Sort: SetSortName, Limit: 2})
+	if err != nil || len(sets.Sets) != 2 || sets.Sets[1].ID != "CRU" || sets.Prev != "" {
+		t.Fatalf("first set page = %+v (%v)", sets, err)
+	}
+	sets, err = store.SearchSetsPage(SetFilter{Sort: SetSortName, Limit: 2, Cursor: sets.Next})
+	if err != nil || len(sets.Sets) != 1 || sets.Sets[0].ID != "WTR" || sets.Next != "" || sets.Prev == "" {
+		t.Fatalf("second set page = %+v (%v)", sets, err)
+	}
+	sets, _ = store.SearchSetsPage(SetFilter{})
+	if len(sets.Sets) != 3 || sets.Sets[0].ID != "WTR" || sets.Next != "" {
+		t.Errorf("unpaged sets = %+v", sets)
+	}
+}
+
+func TestBitmap(t *testing.T) {
+	// Few ordinals stay a list, many become bit words
+	sparse := newBitmap([]uint32{3, 70, 200, 1000})
+	dense := newBitmap([]uint32{0, 1, 2, 3, 4, 5, 6, 7, 64, 70, 71})
+	if sparse.dense != nil || dense.dense == nil {
+		t.Fatalf("newBitmap() forms: sparse dense=%t, dense dense=%t", sparse.dense != nil, dense.dense != nil)
+	}
+
+	ordinals := func(b *Bitmap) []int { return slices.Collect(b.All()) }
+	tests := []struct {
+		name string
+		got  *Bitmap
+		want []int
+	}{
+		{"sparse and dense", sparse.And(dense), []int{3, 70}},
+		{"dense and sparse", dense.And(sparse), []int{3, 70}},
+		{"dense and dense", dense.And(newBitmap([]uint32{1, 2, 3, 4, 5, 6, 7, 8, 9})), []int{1, 2, 3, 4, 5, 6, 7}},
+		{"sparse or sparse", sparse.Or(newBitmap([]uint32{3, 4})), []int{3, 4, 70, 200, 1000}},
+		{"sparse or dense", sparse.Or(dense), []int{0, 1, 2, 3, 4, 5, 6, 7, 64, 70, 71, 200, 1000}},
+		{"dense and not sparse", dense.AndNot(sparse), []int{0, 1, 2, 4, 5, 6, 7, 64, 71}},
+		{"sparse and not dense", sparse.AndNot(dense), []int{200, 1000}},
+		{"empty", sparse.And(&Bitmap{}), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ordinals(tt.got); !slices.Equal(got, tt.want) || tt.got.Len() != len(tt.want) {
+				t.Errorf("ordinals = %v (len %d), want %v", got, tt.got.Len(), tt.want)
+			}
+			for _, ordinal := range tt.want {
+				if !tt.got.Contains(ordinal) {
+					t.Errorf("Contains(%d) = false", ordinal)
+				}
+			}
+		})
+	}
+}
+
+func TestBitmapIndex(t *testing.T) {
+	cards, rarities := syntheticCards(2000)
+	scan := &Store{Cards: cards, RaritiesByID: rarities}
+	indexed := &Store{Cards: cards, RaritiesByID: rarities, Bitmaps: NewBitmapIndex(cards)}
+	mapped := mapIndexedStore(cards, rarities)
+
+	for name, filter := range syntheticFilters(t) {
+		t.Run(name, func(t *testing.T) {
+			want, wantTotal := scan.SearchCards(filter)
+			got, total := indexed.SearchCards(filter)
+			if total != wantTotal || !slices.Equal(got, want) {
+				t.Errorf("SearchCards() = %d cards, want %d as with a full scan", total, wantTotal)
+			}
+			if wantTotal == 0 {
+				t.Errorf("SearchCards() matched no card, the case tests nothing")
+			}
+
+			// The benchmark baseline finds the same cards
+			baseline := indexThenFilter(mapped, filter)
+			if !slices.EqualFunc(baseline, want, func(a domain.ScoredCard, b *domain.Card) bool { return a.Card == b }) {
+				t.Errorf("indexThenFilter() = %d cards, want %d as with a full scan", len(baseline), wantTotal)
+			}
+		})
+	}
+}
+
+func TestResultCache(t *testing.T) {
+	cards, rarities := syntheticCards(200)
+	store := &Store{Cards: cards, RaritiesByID: rarities, Version: "v1", cache: NewResultCache(2, nil)}
+	store.cache.use(store)
+
+	search := func(store *Store, filter CardFilter) CardPage {
+		t.Helper()
+		page, err := store.SearchCardsPage(filter, nil)
+		if err != nil {
+			t.Fatalf("SearchCardsPage() error = %v", err)
+		}
+		return page
+	}
+
+	first := search(store, CardFilter{Class: "Ninja", Limit: 5})
+	if first.Cached || len(first.Cards) != 5 {
+		t.Fatalf("First search: cached %t with %d cards, want a miss with 5", first.Cached, len(first.Cards))
+	}
+
+	// Filters differing only by case, form or page share the results
+	same := CardFilter{Values: []ValueFilter{{Attribute: AttributeClass, Values: []string{"ninja"}, Mode: MatchAll}}, Offset: 5, Limit: 5}
+	if page := search(store, same); !page.Cached || page.Total != first.Total || page.Offset != 5 {
+		t.Errorf("Equivalent filter: cached %t, total %d at %d, want a hit with total %d at 5", page.Cached, page.Total, page.Offset, first.Total)
+	}
+
+	// The default sort ignores the order
+	search(store, CardFilter{Class: "Ninja", Order: OrderDescending})
+	if page := search(store, CardFilter{Class: "Ninja", Order: OrderAscending}); !page.Cached {
+		t.Error("Expected the default sort to share results across orders")
+	}
+	if page := search(store, CardFilter{Class: "Ninja", Sort: CardSortName, Order: OrderDescending}); page.Cached {
+		t.Error("Expected a descending name sort not to share the default sort's results")
+	}
+
+	// Pages are copies, so changing one leaves the cache alone
+	first.Cards[0].Card = nil
+	if page := search(store, CardFilter{Class: "Ninja", Limit: 5}); page.Cards[0].Card == nil {
+		t.Error("Changing a page changed the cached results")
+	}
+
+	// The least recently used results are evicted
+	search(store, CardFilter{Class: "Wizard"})
+	search(store, CardFilter{Class: "Guardian"})
+	if page := search(store, CardFilter{Class: "Ninja"}); page.Cached {
+		t.Error("Expected the least recently used results to be evicted")
+	}
+	if store.cache.Len() != 2 {
+		t.Errorf("Cache holds %d results, want 2", store.cache.Len())
+	}
+
+	// Concurrent searches share the cache
+	var wg sync.WaitGroup
+	for _, class := range []string{"Ninja", "Wizard", "Ninja", "Runeblade", "Wizard", "Generic"} {
+		wg.Go(func() { store.SearchCards(CardFilter{Class: class}) })
+	}
+	wg.Wait()
+
+	// Another store never gets the results, even at the same version, nor
+	// caches its own
+	other := &Store{Cards: cards, RaritiesByID: rarities, Version: "v1", cache: store.cache}
+	search(store, CardFilter{Class: "Ninja"})
+	if page := search(other, CardFilter{Class: "Ninja"}); page.Cached {
+		t.Error("Expected another store not to be served the results")
+	}
+	search(other, CardFilter{Class: "Warrior"})
+	if page := search(other, CardFilter{Class: "Warrior"}); page.Cached {
+		t.Error("Expected another store not to cache results")
+	}
+	if page := search(store, CardFilter{Class: "Ninja"}); !page.Cached {
+		t.Error("Expected another store's searches to leave the results alone")
+	}
+
+	// Replacing the store invalidates the results, even when the data is the same
+	dir := writeDataDir(t, `[{"unique_id": "a", "name": "First", "types": ["Ninja"]}]`)
+	provider, err := NewProvider(ProviderConfig{Dir: dir, CacheSize: 10}, nil)
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	search(provider.Store(), CardFilter{Class: "Ninja"})
+	if page := search(provider.Store(), CardFilter{Class: "Ninja"}); !page.Cached {
+		t.Error("Expected the provider's store to cache results")
+	}
+	previous := provider.Store()
+	if _, err := provider.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if provider.cache.Len() != 0 {
+		t.Errorf("Cache holds %d results after a reload, want none", provider.cache.Len())
+	}
+
+	// Searches still running on the replaced store do not pin it
+	search(previous, CardFilter{Class: "Warrior"})
+	if provider.cache.Len() != 0 {
+		t.Errorf("Cache holds %d results of the replaced store, want none", provider.cache.Len())
+	}
+	if page := search(provider.Store(), CardFilter{Class: "Ninja"}); page.Cached || page.Total != 1 {
+		t.Errorf("After a reload: cached %t with total %d, want a miss with 1", page.Cached, page.Total)
+	}
+}
+
+func TestProviderChangelogFile(t *testing.T) {
+	dir := writeDataDir(t, `[{"unique_id": "a", "name": "First"}]`)
+	config := ProviderConfig{Dir: dir, ChangelogFile: filepath.Join(t.TempDir(), "changelog.json")}
+
+	provider, err := NewProvider(config, nil)
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	if provider.Changelog() != nil {
+		t.Error("Expected no changelog before a reload")
+	}
+
+	cards := `[{"unique_id": "a", "name": "First"}, {"unique_id": "b", "name": "Second"}]`
+	if err := os.WriteFile(filepath.Join(dir, "card.json"), []byte(cards), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := provider.Reload()
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	// A restart on the same data serves the recorded changelog
+	restarted, err := NewProvider(config, nil)
+	if err != nil {
+		t.Fatalf("NewProvider() after a reload error = %v", err)
+	}
+	if changelog := restarted.Changelog(); changelog == nil || changelog.ToVersion != store.Version || changelog.Summary.Added != 1 {
+		t.Errorf("Changelog after a restart = %+v, want the recorded one to %s", changelog, store.Version)
+	}
+
+	// A restart on other data does not
+	if err := os.WriteFile(filepath.Join(dir, "card.json"), []byte(`[{"unique_id": "c", "name": "Third"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	restarted, err = NewProvider(config, nil)
+	if err != nil {
+		t.Fatalf("NewProvider() on other data error = %v", err)
+	}
+	if changelog := restarted.Changelog(); changelog != nil {
+		t.Errorf("Changelog on other data = %+v, want none", changelog)
+	}
+}
+
+// syntheticCards returns n deterministic cards spread over classes, types,
+// keywords, sets, pitches, rarities and legalities, for comparing and timing
+// searches.
+func syntheticCards(n int) ([]*domain.Card, map[string]*domain.Rarity) {
+	classes := []string{"Ninja", "Warrior", "Guardian", "Wizard", "Runeblade", "Generic"}
+	types := []string{"Action", "Attack Reaction", "Defense Reaction", "Instant", "Equipment"}
+	keywords := []string{"Go again", "Dominate", "Combo", "Blade Break", "Arcane Barrier", "Reprise", "Boost"}
+	sets := []string{"WTR", "ARC", "CRU", "MON", "ELE", "EVR", "UPR", "DYN", "OUT", "DTD"}
+	rarities := []string{"C", "R", "M", "L", "T"}
+	colors := []string{"Red", "Yellow", "Blue"}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	cards := make([]*domain.Card, n)
+	for i := range cards {
+		card := &domain.Card{
+			UniqueID:      fmt.Sprintf("card-%d", i),
+			Name:          fmt.Sprintf("Card %d", i),
+			Types:         []string{classes[rng.IntN(len(classes))], types[rng.IntN(len(types))]},
+			CCLegal:       rng.IntN(10) > 0,
+			BlitzLegal:    rng.IntN(3) > 0,
+			BlitzBanned:   rng.IntN(50) == 0,
+			CommonerLegal: rng.IntN(4) == 0,
+		}
+		if rng.IntN(8) == 0 {
+			card.Types = append(card.Types, classes[rng.IntN(len(classes))])
+		}
+		if card.Types[1] != "Equipment" {
+			pitch := rng.IntN(3)
+			card.Pitch = strconv.Itoa(pitch + 1)
+			card.Color = colors[pitch]
+			card.Types = append(card.Types, "Attack")
+		}
+		for range rng.IntN(3) {
+			card.CardKeywords = append(card.CardKeywords, keywords[rng.IntN(len(keywords))])
+		}
+		for range 1 + rng.IntN(2) {
+			card.Printings = append(card.Printings, domain.Printing{
+				SetID:  sets[rng.IntN(len(sets))],
+				Rarity: rarities[rng.IntN(len(rarities))],
+			})
+		}
+		card.ParseStats()
+		cards[i] = card
+	}
+
+	return cards, map[string]*domain.Rarity{"M": {ID: "M", Name: "Majestic"}, "L": {ID: "L", Name: "Legendary"}}
+}
+
+// syntheticFilters returns representative filter combinations over the
+// synthetic cards.
+func syntheticFilters(tb testing.TB) map[string]CardFilter {
+	parse := func(q string) query.Node {
+		expr, err := query.Parse(q)
+		if err != nil {
+			tb.Fatalf("Parse(%s) error = %v", q, err)
+		}
+		return expr
+	}
+
+	return map[string]CardFilter{
+		"class and type":  {Class: "Ninja", Type: "Attack"},
+		"any keyword":     {Values: []ValueFilter{{Attribute: AttributeKeyword, Values: []string{"go again", "combo"}}}, Pitch: "1"},
+		"all classes":     {Values: []ValueFilter{{Attribute: AttributeClass, Values: []string{"Ninja", "Warrior"}, Mode: MatchAll}}},
+		"set and legal":   {Values: []ValueFilter{{Attribute: AttributeSet, Values: []string{"WTR", "ARC", "MON"}}}, LegalIn: domain.FormatBlitz},
+		"negated type":    {Class: "Guardian", Values: []ValueFilter{{Attribute: AttributeType, Values: []string{"Equipment", "Instant"}, Negate: true}}},
+		"name and class":  {Name: "card 1", Class: "wizard"},
+		"query":           {Query: parse(`(c:runeblade or c:wizard) kw:arcane r:legendary -banned:blitz`)},
+		"query and pitch": {Query: parse(`color:red pitch<=1 -t=equipment`), Values: []ValueFilter{{Attribute: AttributeSet, Values: []string{"ELE", "UPR"}}}},
+		"common legal":    {Query: parse(`legal:commoner rarity:c`)},
+	}
+}
+
+// mapIndexedStore returns a store of the cards with the CardsBy* maps built,
+// and no bitmap index.
+func mapIndexedStore(cards []*domain.Card, rarities map[string]*domain.Rarity) *Store {
+	s := &Store{
+		Cards:          cards,
+		RaritiesByID:   rarities,
+		CardsByClass:   make(map[string][]*domain.Card),
+		CardsByType:    make(map[string][]*domain.Card),
+		CardsByKeyword: make(map[string][]*domain.Card),
+		CardsBySetID:   make(map[string][]*domain.Card),
+	}
+	for _, card := range cards {
+		for _, class := range card.Classes() {
+			s.CardsByClass[class] = append(s.CardsByClass[class], card)
+		}
+		for _, cardType := range card.Types {
+			s.CardsByType[cardType] = append(s.CardsByType[cardType], card)
+		}
+		for _, keyword := range card.CardKeywords {
+			s.CardsByKeyword[keyword] = append(s.CardsByKeyword[keyword], card)
+		}
+		for _, printing := range card.Printings {
+			s.CardsBySetID[printing.SetID] = append(s.CardsBySetID[printing.SetID], card)
+		}
+	}
+	return s
+}
+
+// indexThenFilter searches cards the way stores did before the bitmap index,
+// as a baseline: the value filter with the fewest cards in the CardsBy* maps
+// supplies the candidates, and every candidate is checked against the whole
+// filter. The store must have no bitmap index.
+func indexThenFilter(s *Store, filter CardFilter) []domain.ScoredCard {
+	indexes := map[CardAttribute]map[string][]*domain.Card{
+		AttributeClass:   s.CardsByClass,
+		AttributeType:    s.CardsByType,
+		AttributeKeyword: s.CardsByKeyword,
+		AttributeSet:     s.CardsBySetID,
+	}
+	buckets := func(f ValueFilter, value string) [][]*domain.Card {
+		var found [][]*domain.Card
+		for key, cards := range indexes[f.Attribute] {
+			if f.Attribute == AttributeKeyword && strings.Contains(strings.ToLower(key), strings.ToLower(value)) ||
+				f.Attribute != AttributeKeyword && strings.EqualFold(key, value) {
+				found = append(found, cards)
+			}
+		}
+		return found
+	}
+	merge := func(buckets [][]*domain.Card) []*domain.Card {
+		members := make(map[*domain.Card]bool)
+		for _, bucket := range buckets {
+			for _, card := range bucket {
+				members[card] = true
+			}
+		}
+		merged := make([]*domain.Card, 0, len(members))
+		for _, card := range s.Cards {
+			if members[card] {
+				merged = append(merged, card)
+			}
+		}
+		return merged
+	}
+
+	candidates := s.Cards
+	for _, f := range filter.valueFilters() {
+		if f.Negate || len(f.Values) == 0 || indexes[f.Attribute] == nil {
+			continue
+		}
+		if f.Mode == MatchAll {
+			for _, value := range f.Values {
+				if cards := merge(buckets(f, value)); len(cards) < len(candidates) {
+					candidates = cards
+				}
+			}
+			continue
+		}
+		var all [][]*domain.Card
+		for _, value := range f.Values {
+			all = append(all, buckets(f, value)...)
+		}
+		if cards := merge(all); len(cards) < len(candidates) {
+			candidates = cards
+		}
+	}
+
+	m := s.newCardMatcher(filter)
+	var results []domain.ScoredCard
+	for _, card := range candidates {
+		if m.matches(card, 0) {
+			results = append(results, domain.ScoredCard{Card: card})
+		}
+	}
+	s.sortCards(results, filter.Sort, filter.Order)
+	return results
+}
+
+// BenchmarkSearchCards compares bitmap searches with the searches they
+// replaced, which narrowed the cards through the CardsBy* maps, and with a
+// store without any index, which checks every card.
+func BenchmarkSearchCards(b *testing.B) {
+	cards, rarities := syntheticCards(10000)
+	scan := &Store{Cards: cards, RaritiesByID: rarities}
+	indexed := mapIndexedStore(cards, rarities)
+	bitmap := &Store{Cards: cards, RaritiesByID: rarities, Bitmaps: NewBitmapIndex(cards)}
+
+	searches := []struct {
+		name   string
+		search func(CardFilter)
+	}{
+		{"scan", func(filter CardFilter) { scan.SearchCards(filter) }},
+		{"index", func(filter CardFilter) { indexThenFilter(indexed, filter) }},
+		{"bitmap", func(filter CardFilter) { bitmap.SearchCards(filter) }},
+	}
+
+	filters := syntheticFilters(b)
+	names := slices.Sorted(maps.Keys(filters))
+	for _, name := range names {
+		for _, s := range searches {
+			b.Run(name+"/"+s.name, func(b *testing.B) {
+				for b.Loop() {
+					s.search(filters[name])
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package data
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+// BM25 parameters and per-field weights: a term in a card's name counts for
+// more than one in its type line, which counts for more than one in its text.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	nameWeight     = 3.0
+	typeTextWeight = 2.0
+	textWeight     = 1.0
+
+	// fieldSpan separates the positions of each field so phrases never match
+	// across two fields.
+	fieldSpan = 1 << 20
+)
+
+// TextIndex is an inverted index over card names, type lines and functional
+// text, ranking matches with BM25.
+type TextIndex struct {
+	cards      []*domain.Card
+	postings   map[string][]posting
+	vocabulary []string // sorted, for prefix queries
+	docLen     []float64
+	avgDocLen  float64
+}
+
+// posting records the occurrences of a term in a card.
+type posting struct {
+	doc       int
+	freq      float64 // weighted by field
+	positions []int
+}
+
+// TextHit is a card matching a text query, with its relevance score.
+type TextHit struct {
+	Card  *domain.Card
+	Score float64
+
+	// Ordinal is the position of the card in the indexed cards
+	Ordinal int
+}
+
+// NewTextIndex builds a text index over the given cards.
+func NewTextIndex(cards []*domain.Card) *TextIndex {
+	ix := &TextIndex{
+		cards:    cards,
+		postings: make(map[string][]posting),
+		docLen:   make([]float64, len(cards)),
+	}
+
+	var totalLen float64
+	for doc, card := range cards {
+		fields := []struct {
+			text   string
+			weight float64
+		}{
+			{card.Name, nameWeight},
+			{card.TypeText, typeTextWeight},
+			{card.FunctionalTextPlain, textWeight},
+		}
+
+		for f, field := range fields {
+			for pos, token := range tokenize(field.text) {
+				ix.addOccurrence(token, doc, field.weight, f*fieldSpan+pos)
+				ix.docLen[doc] += field.weight
+			}
+		}
+		totalLen += ix.docLen[doc]
+	}
+
+	if len(cards) > 0 {
+		ix.avgDocLen = totalLen / float64(len(cards))
+	}
+
+	ix.vocabulary = make([]string, 0, len(ix.postings))
+	for term := range ix.postings {
+		ix.vocabulary = append(ix.vocabulary, term)
+	}
+	sort.Strings(ix.vocabulary)
+
+	return ix
+}
+
+func (ix *TextIndex) addOccurrence(term string, doc int, weight float64, position int) {
+	list := ix.postings[term]
+	if n := len(list); n > 0 && list[n-1].doc == doc {
+		list[n-1].freq += weight
+		list[n-1].positions = append(list[n-1].positions, position)
+		return
+	}
+	ix.postings[term] = append(list, posting{doc: doc, freq: weight, positions: []int{position}})
+}
+
+// hasTextClauses reports whether a text query has any words to search for,
+// rather than only punctuation.
+func hasTextClauses(query string) bool {
+	return len(parseTextQuery(query)) > 0
+}
+
+// Search returns the cards matching every clause of the query, most relevant
+// first. Clauses are words, "quoted phrases" and prefixes ending in '*'.
+func (ix *TextIndex) Search(query string) []TextHit {
+	clauses := parseTextQuery(query)
+	if len(clauses) == 0 {
+		return nil
+	}
+
+	var scores map[int]float64
+	for _, clause := range clauses {
+		matches := ix.evaluate(clause)
+		if scores == nil {
+			scores = matches
+			continue
+		}
+		for doc, score := range scores {
+			if extra, ok := matches[doc]; ok {
+				scores[doc] = score + extra
+			} else {
+				delete(scores, doc)
+			}
+		}
+	}
+
+	// Ties keep file order, so results are stable
+	docs := make([]int, 0, len(scores))
+	for doc := range scores {
+		docs = append(docs, doc)
+	}
+	slices.SortFunc(docs, func(a, b int) int {
+		if scores[a] != scores[b] {
+			if scores[a] > scores[b] {
+				return -1
+			}
+			return 1
+		}
+		return a - b
+	})
+
+	hits := make([]TextHit, len(docs))
+	for i, doc := range docs {
+		hits[i] = TextHit{Card: ix.cards[doc], Score: scores[doc], Ordinal: doc}
+	}
+
+	return hits
+}
+
+// textClause is one part of a text query.
+type textClause struct {
+	terms  []string // several for a phrase, or the forms of a prefix
+	prefix bool
+}
+
+func parseTextQuery(query string) []textClause {
+	var clauses []textClause
+
+	for query != "" {
+		start := strings.IndexByte(query, '"')
+		if start < 0 {
+			clauses = append(clauses, parseWords(query)...)
+			break
+		}
+
+		clauses = append(clauses, parseWords(query[:start])...)
+		query = query[start+1:]
+
+		// An unterminated quote runs to the end of the query
+		end := strings.IndexByte(query, '"')
+		if end < 0 {
+			end = len(query)
+		}
+		if terms := tokenize(query[:end]); len(terms) > 0 {
+			clauses = append(clauses, textClause{terms: terms})
+		}
+		query = query[min(end+1, len(query)):]
+	}
+
+	return clauses
+}
+
+func parseWords(text string) []textClause {
+	var clauses []textClause
+	for _, word := range strings.Fields(text) {
+		if prefix, ok := strings.CutSuffix(word, "*"); ok {
+			// Terms are indexed stemmed, so "attacks*" looks for "attack"
+			// too, while "abilit*" is only a prefix as it is
+			for _, token := range splitWords(prefix) {
+				terms := []string{token}
+				if stemmed := stem(token); stemmed != token {
+					terms = append(terms, stemmed)
+				}
+				clauses = append(clauses, textClause{terms: terms, prefix: true})
+			}
+			continue
+		}
+		for _, token := range tokenize(word) {
+			clauses = append(clauses, textClause{terms: []string{token}})
+		}
+	}
+	return clauses
+}
+
+// evaluate returns the BM25 score of every card matching the clause.
+func (ix *TextIndex) evaluate(clause textClause) map[int]float64 {
+	scores := make(map[int]float64)
+
+	switch {
+	case clause.prefix:
+		// Score a prefix by its best matching term in each card
+		for _, prefix := range clause.terms {
+			from := sort.SearchStrings(ix.vocabulary, prefix)
+			for _, term := range ix.vocabulary[from:] {
+				if !strings.HasPrefix(term, prefix) {
+					break
+				}
+				for doc, score := range ix.termScores(term) {
+					scores[doc] = max(scores[doc], score)
+				}
+			}
+		}
+
+	case len(clause.terms) == 1:
+		scores = ix.termScores(clause.terms[0])
+
+	default:
+		for doc := range ix.phraseDocs(clause.terms) {
+			for _, term := range clause.terms {
+				scores[doc] += ix.termScore(term, doc)
+			}
+		}
+	}
+
+	return scores
+}
+
+func (ix *TextIndex) termScores(term string) map[int]float64 {
+	list := ix.postings[term]
+	scores := make(map[int]float64, len(list))
+	idf := ix.idf(len(list))
+	for _, p := range list {
+		scores[p.doc] = idf * ix.saturate(p.freq, p.doc)
+	}
+	return scores
+}
+
+func (ix *TextIndex) termScore(term string, doc int) float64 {
+	list := ix.postings[term]
+	i, ok := slices.BinarySearchFunc(list, doc, func(p posting, doc int) int { return p.doc - doc })
+	if !ok {
+		return 0
+	}
+	return ix.idf(len(list)) * ix.saturate(list[i].freq, doc)
+}
+
+func (ix *TextIndex) idf(docFreq int) float64 {
+	n := float64(len(ix.cards))
+	df := float64(docFreq)
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+func (ix *TextIndex) saturate(freq float64, doc int) float64 {
+	norm := 1 - bm25B + bm25B*ix.docLen[doc]/ix.avgDocLen
+	return freq * (bm25K1 + 1) / (freq + bm25K1*norm)
+}
+
+// phraseDocs returns the cards containing the terms at consecutive positions.
+func (ix *TextIndex) phraseDocs(terms []string) map[int]bool {
+	positions := make([]map[int][]int, len(terms))
+	for i, term := range terms {
+		positions[i] = make(map[int][]int)
+		for _, p := range ix.postings[term] {
+			positions[i][p.doc] = p.positions
+		}
+	}
+
+	docs := make(map[int]bool)
+	for doc, starts := range positions[0] {
+	next:
+		for _, start := range starts {
+			for i := 1; i < len(terms); i++ {
+				if _, ok := slices.BinarySearch(positions[i][doc], start+i); !ok {
+					continue next
+				}
+			}
+			docs[doc] = true
+			break
+		}
+	}
+	return docs
+}
+
+// tokenize splits text into lower-cased, stemmed terms.
+func tokenize(text string) []string {
+	words := splitWords(text)
+	for i, word := range words {
+		words[i] = stem(word)
+	}
+	return words
+}
+
+// splitWords splits text into lower-cased words of letters and digits,
+// dropping possessive suffixes.
+func splitWords(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’'
+	})
+
+	result := words[:0]
+	for _, word := range words {
+		word = strings.TrimSuffix(strings.TrimSuffix(word, "'s"), "’s")
+		word = strings.Trim(word, "'’")
+		if word != "" {
+			result = append(result, word)
+		}
+	}
+	return result
+}
+
+// stem strips plural endings, so "attacks" and "attack", or "abilities" and
+// "ability", index as the same term.
+func stem(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 4 && strings.HasSuffix(word, "sses"):
+		return word[:len(word)-2]
+	case len(word) > 4 && (strings.HasSuffix(word, "xes") || strings.HasSuffix(word, "ches") || strings.HasSuffix(word, "shes")):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") &&
+		!strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
@@ -0,0 +1,205 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+// IssueCode identifies the kind of problem a validation issue reports.
+type IssueCode string
+
+const (
+	IssueBrokenReference IssueCode = "broken_reference"
+	IssueDuplicateID     IssueCode = "duplicate_id"
+	IssueUnknownCode     IssueCode = "unknown_code"
+)
+
+// ValidationIssue is a problem found in the loaded data, located by the file
+// and record it was found in.
+type ValidationIssue struct {
+	Code    IssueCode `json:"code"`
+	File    string    `json:"file"`
+	Record  string    `json:"record"`
+	Field   string    `json:"field"`
+	Value   string    `json:"value"`
+	Message string    `json:"message"`
+}
+
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s: %s", i.File, i.Record, i.Field, i.Message)
+}
+
+// ValidationError is returned when strict loading finds issues in the data.
+type ValidationError struct {
+	Issues []ValidationIssue
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Issues) == 1 {
+		return "data validation failed: " + e.Issues[0].String()
+	}
+	return fmt.Sprintf("data validation failed with %d issues, first: %s", len(e.Issues), e.Issues[0])
+}
+
+// Validate checks the loaded data for broken cross-references between files,
+// duplicate IDs and unknown printing codes, and returns every issue found.
+func (s *Store) Validate() []ValidationIssue {
+	v := &validator{store: s}
+
+	v.validateCards()
+	v.validateSets()
+	v.validateLegality()
+	v.validateReferences()
+	v.validateFaces()
+
+	return v.issues
+}
+
+type validator struct {
+	store  *Store
+	issues []ValidationIssue
+}
+
+func (v *validator) add(code IssueCode, file, record, field, value, message string) {
+	v.issues = append(v.issues, ValidationIssue{
+		Code:    code,
+		File:    file,
+		Record:  record,
+		Field:   field,
+		Value:   value,
+		Message: message,
+	})
+}
+
+func cardRecord(i int, card *domain.Card) string {
+	return fmt.Sprintf("card #%d %s (%s)", i, card.UniqueID, card.Name)
+}
+
+func (v *validator) validateCards() {
+	s := v.store
+	cards := make(map[string]bool)
+	printings := make(map[string]string)
+
+	for i, card := range s.Cards {
+		record := cardRecord(i, card)
+
+		if cards[card.UniqueID] {
+			v.add(IssueDuplicateID, "card.json", record, "unique_id", card.UniqueID, "duplicate card unique_id")
+		}
+		cards[card.UniqueID] = true
+
+		for _, cardType := range card.Types {
+			if _, ok := s.TypesByName[cardType]; !ok {
+				v.add(IssueUnknownCode, "card.json", record, "types", cardType,
+					fmt.Sprintf("type %q is not in type.json", cardType))
+			}
+		}
+
+		for j, printing := range card.Printings {
+			field := func(name string) string {
+				return fmt.Sprintf("printings[%d].%s", j, name)
+			}
+
+			if owner, ok := printings[printing.UniqueID]; ok {
+				v.add(IssueDuplicateID, "card.json", record, field("unique_id"), printing.UniqueID,
+					fmt.Sprintf("printing unique_id already used by card %s", owner))
+			}
+			printings[printing.UniqueID] = card.UniqueID
+
+			if _, ok := s.SetsByID[printing.SetID]; !ok {
+				v.add(IssueBrokenReference, "card.json", record, field("set_id"), printing.SetID,
+					fmt.Sprintf("set %q is not in set.json", printing.SetID))
+			}
+			if _, ok := s.EditionsByID[printing.Edition]; !ok {
+				v.add(IssueUnknownCode, "card.json", record, field("edition"), printing.Edition,
+					fmt.Sprintf("edition %q is not in edition.json", printing.Edition))
+			}
+			if _, ok := s.FoilingsByID[printing.Foiling]; !ok {
+				v.add(IssueUnknownCode, "card.json", record, field("foiling"), printing.Foiling,
+					fmt.Sprintf("foiling %q is not in foiling.json", printing.Foiling))
+			}
+			if _, ok := s.RaritiesByID[printing.Rarity]; !ok {
+				v.add(IssueUnknownCode, "card.json", record, field("rarity"), printing.Rarity,
+					fmt.Sprintf("rarity %q is not in rarity.json", printing.Rarity))
+			}
+			for _, variation := range printing.ArtVariations {
+				if _, ok := s.ArtVariationsByID[variation]; !ok {
+					v.add(IssueUnknownCode, "card.json", record, field("art_variations"), variation,
+						fmt.Sprintf("art variation %q is not in art-variation.json", variation))
+				}
+			}
+			for _, info := range printing.DoubleSidedCardInfo {
+				if !v.isFace(info.OtherFaceUniqueID) {
+					v.add(IssueBrokenReference, "card.json", record, field("double_sided_card_info"), info.OtherFaceUniqueID,
+						"other face is not a known card or printing")
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) validateSets() {
+	seen := make(map[string]bool)
+	for i, set := range v.store.Sets {
+		if seen[set.ID] {
+			v.add(IssueDuplicateID, "set.json", fmt.Sprintf("set #%d %s (%s)", i, set.ID, set.Name), "id", set.ID, "duplicate set id")
+		}
+		seen[set.ID] = true
+	}
+}
+
+func (v *validator) validateLegality() {
+	files := make(map[domain.Format]map[domain.LegalityStatus]string)
+	for _, source := range legalityFiles {
+		if files[source.format] == nil {
+			files[source.format] = make(map[domain.LegalityStatus]string)
+		}
+		files[source.format][source.status] = source.file
+	}
+
+	seen := make(map[string]bool)
+	for _, event := range v.store.LegalityEvents {
+		file := files[event.Format][event.Status]
+		record := fmt.Sprintf("record %s", event.UniqueID)
+
+		if seen[event.UniqueID] {
+			v.add(IssueDuplicateID, file, record, "unique_id", event.UniqueID, "duplicate legality record unique_id")
+		}
+		seen[event.UniqueID] = true
+
+		if v.store.CardsByID[event.CardUniqueID] == nil {
+			v.add(IssueBrokenReference, file, record, "card_unique_id", event.CardUniqueID, "card is not in card.json")
+		}
+	}
+}
+
+func (v *validator) validateReferences() {
+	for i, ref := range v.store.CardReferences {
+		record := fmt.Sprintf("reference #%d", i)
+		if v.store.CardsByID[ref.CardUniqueID] == nil {
+			v.add(IssueBrokenReference, "card-reference.json", record, "card_unique_id", ref.CardUniqueID, "card is not in card.json")
+		}
+		if v.store.CardsByID[ref.ReferencedCardUniqueID] == nil {
+			v.add(IssueBrokenReference, "card-reference.json", record, "referenced_card_unique_id", ref.ReferencedCardUniqueID, "card is not in card.json")
+		}
+	}
+}
+
+func (v *validator) validateFaces() {
+	for i, assoc := range v.store.FaceAssociations {
+		record := fmt.Sprintf("association #%d", i)
+		if !v.isFace(assoc.FrontUniqueID) {
+			v.add(IssueBrokenReference, "card-face-association.json", record, "front_unique_id", assoc.FrontUniqueID, "face is not a known card or printing")
+		}
+		if !v.isFace(assoc.BackUniqueID) {
+			v.add(IssueBrokenReference, "card-face-association.json", record, "back_unique_id", assoc.BackUniqueID, "face is not a known card or printing")
+		}
+	}
+}
+
+// isFace reports whether a face ID resolves, as face IDs may name either a
+// printing or a card.
+func (v *validator) isFace(id string) bool {
+	return v.store.PrintingsByUniqueID[id] != nil || v.store.CardsByID[id] != nil
+}
@@ -0,0 +1,140 @@
+package data
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+// CardAttribute is a card attribute that value filters match against.
+type CardAttribute string
+
+const (
+	AttributeClass   CardAttribute = "class"
+	AttributeType    CardAttribute = "type"
+	AttributeKeyword CardAttribute = "keyword" // matched partially
+	AttributeSet     CardAttribute = "set"
+	AttributePitch   CardAttribute = "pitch"
+)
+
+// CardAttributes lists the attributes value filters accept.
+var CardAttributes = []CardAttribute{
+	AttributeClass, AttributeType, AttributeKeyword, AttributeSet, AttributePitch,
+}
+
+// MatchMode is how a value filter combines its values.
+type MatchMode string
+
+const (
+	MatchAny MatchMode = "any" // the card has at least one of the values
+	MatchAll MatchMode = "all" // the card has every value
+)
+
+// ParseMatchMode parses a match mode. An empty value selects MatchAny.
+func ParseMatchMode(value string) (MatchMode, error) {
+	switch mode := MatchMode(strings.ToLower(value)); mode {
+	case "", MatchAny:
+		return MatchAny, nil
+	case MatchAll:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid mode %q, expected any or all", value)
+	}
+}
+
+// ValueFilter matches a card attribute against one or more values. Negated
+// filters exclude the cards having any of the values, whatever the mode.
+type ValueFilter struct {
+	Attribute CardAttribute
+	Values    []string
+	Mode      MatchMode
+	Negate    bool
+}
+
+// SplitValues splits a comma-separated list of filter values, dropping blank
+// entries.
+func SplitValues(list string) []string {
+	var values []string
+	for value := range strings.SplitSeq(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// valueFilters returns the filter's value filters, including the single
+// value fields such as Type and Keyword.
+func (f CardFilter) valueFilters() []ValueFilter {
+	filters := f.Values
+	single := []struct {
+		attribute CardAttribute
+		value     string
+	}{
+		{AttributeClass, f.Class},
+		{AttributeType, f.Type},
+		{AttributeKeyword, f.Keyword},
+		{AttributeSet, f.SetID},
+		{AttributePitch, f.Pitch},
+	}
+	for _, field := range single {
+		if field.value != "" {
+			filters = append(slices.Clip(filters), ValueFilter{
+				Attribute: field.attribute,
+				Values:    []string{field.value},
+				Mode:      MatchAny,
+			})
+		}
+	}
+	return filters
+}
+
+// Matches reports whether the card passes the filter. Filters without values
+// match every card.
+func (f ValueFilter) Matches(card *domain.Card) bool {
+	if len(f.Values) == 0 {
+		return true
+	}
+
+	has := func(value string) bool { return hasAttributeValue(card, f.Attribute, value) }
+	switch {
+	case f.Negate:
+		return !slices.ContainsFunc(f.Values, has)
+	case f.Mode == MatchAll:
+		for _, value := range f.Values {
+			if !has(value) {
+				return false
+			}
+		}
+		return true
+	default:
+		return slices.ContainsFunc(f.Values, has)
+	}
+}
+
+// hasAttributeValue reports whether a card has a value of an attribute.
+// Values are compared ignoring case, and keywords match partially.
+func hasAttributeValue(card *domain.Card, attribute CardAttribute, value string) bool {
+	equal := func(s string) bool { return strings.EqualFold(s, value) }
+	switch attribute {
+	case AttributeClass:
+		return slices.ContainsFunc(card.Classes(), equal)
+	case AttributeType:
+		return slices.ContainsFunc(card.Types, equal)
+	case AttributeKeyword:
+		value = strings.ToLower(value)
+		return slices.ContainsFunc(card.CardKeywords, func(k string) bool {
+			return strings.Contains(strings.ToLower(k), value)
+		})
+	case AttributeSet:
+		return slices.ContainsFunc(card.Printings, func(p domain.Printing) bool {
+			return equal(p.SetID)
+		})
+	case AttributePitch:
+		return card.Pitch == value
+	default:
+		return false
+	}
+}
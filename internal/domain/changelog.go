@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// ChangeKind groups the fields a card change touches.
+type ChangeKind string
+
+const (
+	ChangeText     ChangeKind = "text"
+	ChangeStats    ChangeKind = "stats"
+	ChangeLegality ChangeKind = "legality"
+)
+
+// FieldChange is a change to one field of a card between two data versions.
+type FieldChange struct {
+	Field  string     `json:"field"`
+	Kind   ChangeKind `json:"kind"`
+	Before string     `json:"before"`
+	After  string     `json:"after"`
+}
+
+// CardChange lists the changes to a card present in both data versions.
+type CardChange struct {
+	CardSummary
+	Changes []FieldChange `json:"changes"`
+}
+
+// ChangelogSummary counts the entries of a changelog.
+type ChangelogSummary struct {
+	Added    int `json:"added"`
+	Removed  int `json:"removed"`
+	Changed  int `json:"changed"`
+	Text     int `json:"text_changes"`
+	Stats    int `json:"stats_changes"`
+	Legality int `json:"legality_changes"`
+}
+
+// Changelog lists the cards added, removed and changed between two data versions.
+type Changelog struct {
+	FromVersion string           `json:"from_version"`
+	ToVersion   string           `json:"to_version"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Summary     ChangelogSummary `json:"summary"`
+	Added       []CardSummary    `json:"added"`
+	Removed     []CardSummary    `json:"removed"`
+	Changed     []CardChange     `json:"changed"`
+}
+
+// Status returns a single word describing the legality: the restriction in
+// force, "legal", or "not_legal" for cards outside the format's card pool.
+func (l Legality) Status() string {
+	switch {
+	case l.Banned:
+		return string(StatusBanned)
+	case l.Suspended:
+		return string(StatusSuspended)
+	case l.LivingLegend:
+		return string(StatusLivingLegend)
+	case l.Restricted:
+		return string(StatusRestricted)
+	case l.Legal:
+		return "legal"
+	default:
+		return "not_legal"
+	}
+}
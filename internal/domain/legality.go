@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// LegalityStatus identifies a restriction that can be placed on a card in a format.
+type LegalityStatus string
+
+const (
+	StatusBanned       LegalityStatus = "banned"
+	StatusSuspended    LegalityStatus = "suspended"
+	StatusLivingLegend LegalityStatus = "living_legend"
+	StatusRestricted   LegalityStatus = "restricted"
+)
+
+// LegalityEvent is a dated change to a card's status in a format, as published
+// in a banned, suspended, living legend or restricted announcement.
+//
+// An event with Active set to true puts the status in force from DateInEffect;
+// an event with Active set to false lifts it.
+type LegalityEvent struct {
+	UniqueID         string         `json:"unique_id"`
+	CardUniqueID     string         `json:"card_unique_id"`
+	Format           Format         `json:"format"`
+	Status           LegalityStatus `json:"status"`
+	Active           bool           `json:"status_active"`
+	DateAnnounced    time.Time      `json:"date_announced"`
+	DateInEffect     time.Time      `json:"date_in_effect"`
+	PlannedEnd       string         `json:"planned_end,omitempty"`
+	AffectsFullCycle bool           `json:"affects_full_cycle,omitempty"`
+	LegalityArticle  string         `json:"legality_article"`
+}
+
+// LegalityHistory holds a card's legality events per format, each timeline
+// ordered by effective date.
+type LegalityHistory map[Format][]*LegalityEvent
+
+// Add inserts an event into the history, keeping its format's timeline ordered.
+func (h LegalityHistory) Add(event *LegalityEvent) {
+	timeline := append(h[event.Format], event)
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].DateInEffect.Before(timeline[j].DateInEffect)
+	})
+	h[event.Format] = timeline
+}
+
+// ActiveAt returns the event that put the status in force for the format at
+// the given time, or nil if the status was not in force then.
+func (h LegalityHistory) ActiveAt(format Format, status LegalityStatus, t time.Time) *LegalityEvent {
+	var latest *LegalityEvent
+	for _, event := range h[format] {
+		if event.Status != status {
+			continue
+		}
+		if event.DateInEffect.After(t) {
+			break
+		}
+		latest = event
+	}
+
+	if latest == nil || !latest.Active {
+		return nil
+	}
+	return latest
+}
+
+// GetLegalityAt returns the legality of a card in a format as it stood at the
+// given time, replaying the card's dated legality history rather than relying
+// on the current status flags.
+func (c *Card) GetLegalityAt(format Format, history LegalityHistory, t time.Time) Legality {
+	legality := Legality{
+		Format:       format,
+		Banned:       history.ActiveAt(format, StatusBanned, t) != nil,
+		Suspended:    history.ActiveAt(format, StatusSuspended, t) != nil,
+		LivingLegend: history.ActiveAt(format, StatusLivingLegend, t) != nil,
+		Restricted:   history.ActiveAt(format, StatusRestricted, t) != nil,
+	}
+
+	legality.Legal = c.inFormatPool(format) &&
+		!legality.Banned && !legality.Suspended && !legality.LivingLegend && !legality.Restricted
+
+	return legality
+}
+
+// inFormatPool reports whether the card is part of the format's card pool,
+// regardless of any ban, suspension or restriction.
+func (c *Card) inFormatPool(format Format) bool {
+	switch format {
+	case FormatBlitz:
+		return c.BlitzLegal
+	case FormatCC:
+		return c.CCLegal
+	case FormatCommoner:
+		return c.CommonerLegal
+	case FormatLL:
+		return c.LLLegal
+	case FormatSilverAge:
+		return c.SilverAgeLegal
+	case FormatUPF:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseDate parses a date given either as YYYY-MM-DD or as an RFC 3339 timestamp.
+func ParseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// BanListEntry is a card placed under a legality status in a format, along with
+// the announcement that put it there.
+type BanListEntry struct {
+	CardUniqueID    string    `json:"card_unique_id"`
+	CardName        string    `json:"card_name"`
+	Pitch           string    `json:"pitch,omitempty"`
+	DateAnnounced   time.Time `json:"date_announced"`
+	DateInEffect    time.Time `json:"date_in_effect"`
+	PlannedEnd      string    `json:"planned_end,omitempty"`
+	LegalityArticle string    `json:"legality_article"`
+}
+
+// BanList lists the cards under each legality status in a format at a point in time.
+type BanList struct {
+	FormatInfo
+	AsOf         string         `json:"as_of"`
+	Banned       []BanListEntry `json:"banned"`
+	Suspended    []BanListEntry `json:"suspended"`
+	LivingLegend []BanListEntry `json:"living_legend"`
+	Restricted   []BanListEntry `json:"restricted"`
+}
+
+// LegalityChange is a legality event enriched for change listings.
+type LegalityChange struct {
+	*LegalityEvent
+	CardName string `json:"card_name"`
+
+	// Scheduled is set for changes that have been announced but are not in
+	// effect yet.
+	Scheduled bool `json:"scheduled"`
+}
+
+// ReasonCode identifies why a card is not legal in a format.
+type ReasonCode string
+
+const (
+	ReasonNotInFormat  ReasonCode = "not_in_format"
+	ReasonNotReleased  ReasonCode = "not_released"
+	ReasonBanned       ReasonCode = ReasonCode(StatusBanned)
+	ReasonSuspended    ReasonCode = ReasonCode(StatusSuspended)
+	ReasonLivingLegend ReasonCode = ReasonCode(StatusLivingLegend)
+	ReasonRestricted   ReasonCode = ReasonCode(StatusRestricted)
+)
+
+// LegalityReason is one reason a card is not legal in a format.
+type LegalityReason struct {
+	Code       ReasonCode `json:"code"`
+	Start      *time.Time `json:"start,omitempty"`
+	End        *time.Time `json:"end,omitempty"`
+	PlannedEnd string     `json:"planned_end,omitempty"`
+	Article    string     `json:"article,omitempty"`
+}
+
+// EndOf returns when the status put in force by the event was lifted, or nil
+// if it has not been lifted (or scheduled to be).
+func (h LegalityHistory) EndOf(event *LegalityEvent) *time.Time {
+	for _, next := range h[event.Format] {
+		if next.Status != event.Status || !next.DateInEffect.After(event.DateInEffect) {
+			continue
+		}
+		if !next.Active {
+			end := next.DateInEffect
+			return &end
+		}
+	}
+	return nil
+}
+
+// Explain fills in the reasons the card is not legal in the legality's format,
+// dating each status from the card's legality history as it stood at t.
+func (l *Legality) Explain(c *Card, history LegalityHistory, t time.Time) {
+	l.Reasons = nil
+
+	if !c.inFormatPool(l.Format) {
+		l.Reasons = append(l.Reasons, LegalityReason{Code: ReasonNotInFormat})
+	}
+
+	statuses := []struct {
+		status LegalityStatus
+		active bool
+	}{
+		{StatusBanned, l.Banned},
+		{StatusSuspended, l.Suspended},
+		{StatusLivingLegend, l.LivingLegend},
+		{StatusRestricted, l.Restricted},
+	}
+
+	for _, s := range statuses {
+		if !s.active {
+			continue
+		}
+
+		reason := LegalityReason{Code: ReasonCode(s.status)}
+		if event := history.ActiveAt(l.Format, s.status, t); event != nil {
+			start := event.DateInEffect
+			reason.Start = &start
+			reason.End = history.EndOf(event)
+			reason.PlannedEnd = event.PlannedEnd
+			reason.Article = event.LegalityArticle
+		}
+		l.Reasons = append(l.Reasons, reason)
+	}
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+)
+
+// StatName names a numeric card stat.
+type StatName string
+
+const (
+	StatPitch        StatName = "pitch"
+	StatCost         StatName = "cost"
+	StatPower        StatName = "power"
+	StatDefense      StatName = "defense"
+	StatHealth       StatName = "health"
+	StatIntelligence StatName = "intelligence"
+	StatArcane       StatName = "arcane"
+)
+
+// StatNames lists every numeric card stat.
+var StatNames = []StatName{StatPitch, StatCost, StatPower, StatDefense, StatHealth, StatIntelligence, StatArcane}
+
+// Stat is a card stat parsed from its printed value.
+type Stat struct {
+	// Value is the printed number, or 0 when the stat is variable.
+	Value int `json:"value"`
+	// Variable is set for values that are not a fixed number, such as "X" or "*".
+	Variable bool `json:"variable,omitempty"`
+}
+
+// ParseStat parses a printed stat. It returns nil for cards without the stat.
+func ParseStat(value string) *Stat {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return &Stat{Value: n}
+	}
+	return &Stat{Variable: true}
+}
+
+// CardStats holds a card's parsed numeric stats. Stats the card does not
+// have are nil.
+type CardStats struct {
+	Pitch        *Stat `json:"pitch,omitempty"`
+	Cost         *Stat `json:"cost,omitempty"`
+	Power        *Stat `json:"power,omitempty"`
+	Defense      *Stat `json:"defense,omitempty"`
+	Health       *Stat `json:"health,omitempty"`
+	Intelligence *Stat `json:"intelligence,omitempty"`
+	Arcane       *Stat `json:"arcane,omitempty"`
+}
+
+// Get returns the named stat, or nil if the card does not have it.
+func (s CardStats) Get(name StatName) *Stat {
+	switch name {
+	case StatPitch:
+		return s.Pitch
+	case StatCost:
+		return s.Cost
+	case StatPower:
+		return s.Power
+	case StatDefense:
+		return s.Defense
+	case StatHealth:
+		return s.Health
+	case StatIntelligence:
+		return s.Intelligence
+	case StatArcane:
+		return s.Arcane
+	default:
+		return nil
+	}
+}
+
+// ParseStats fills Stats from the card's printed stat strings.
+func (c *Card) ParseStats() {
+	c.Stats = CardStats{
+		Pitch:        ParseStat(c.Pitch),
+		Cost:         ParseStat(c.Cost),
+		Power:        ParseStat(c.Power),
+		Defense:      ParseStat(c.Defense),
+		Health:       ParseStat(c.Health),
+		Intelligence: ParseStat(c.Intelligence),
+		Arcane:       ParseStat(c.Arcane),
+	}
+}
@@ -1,7 +1,16 @@
 // Package domain contains the core domain types for Flesh and Blood card data.
 package domain
 
-import "slices"
+import (
+	"slices"
+	"strings"
+)
+
+// ScoredCard is a card returned by a ranked search, with its relevance score.
+type ScoredCard struct {
+	*Card
+	Score float64 `json:"score,omitempty"`
+}
 
 // Card represents a unique Flesh and Blood card.
 type Card struct {
@@ -15,6 +24,7 @@ type Card struct {
 	Health                       string     `json:"health"`
 	Intelligence                 string     `json:"intelligence"`
 	Arcane                       string     `json:"arcane"`
+	Stats                        CardStats  `json:"stats"`
 	Types                        []string   `json:"types"`
 	Traits                       []string   `json:"traits"`
 	CardKeywords                 []string   `json:"card_keywords"`
@@ -25,6 +35,7 @@ type Card struct {
 	InteractsWithKeywords        []string   `json:"interacts_with_keywords"`
 	FunctionalText               string     `json:"functional_text"`
 	FunctionalTextPlain          string     `json:"functional_text_plain"`
+	FunctionalTextRendered       string     `json:"functional_text_rendered,omitempty"`
 	TypeText                     string     `json:"type_text"`
 	PlayedHorizontally           bool       `json:"played_horizontally"`
 	BlitzLegal                   bool       `json:"blitz_legal"`
@@ -139,9 +150,26 @@ type Type struct {
 
 // Rarity represents a card rarity.
 type Rarity struct {
-	UniqueID string `json:"unique_id"`
-	ID       string `json:"id"`
-	Name     string `json:"name"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Foiling represents a printing's foiling treatment.
+type Foiling struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Edition represents a printing's edition.
+type Edition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ArtVariation represents an art treatment a printing can carry.
+type ArtVariation struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 // Format represents a game format for legality checks.
@@ -156,6 +184,32 @@ const (
 	FormatUPF       Format = "upf"
 )
 
+// FormatInfo describes a game format.
+type FormatInfo struct {
+	ID   Format `json:"id"`
+	Name string `json:"name"`
+}
+
+// Formats lists every supported format, in display order.
+var Formats = []FormatInfo{
+	{ID: FormatBlitz, Name: "Blitz"},
+	{ID: FormatCC, Name: "Classic Constructed"},
+	{ID: FormatCommoner, Name: "Commoner"},
+	{ID: FormatLL, Name: "Living Legend"},
+	{ID: FormatSilverAge, Name: "Silver Age"},
+	{ID: FormatUPF, Name: "Ultimate Pit Fight"},
+}
+
+// GetFormatInfo returns the format with the given ID (case-insensitive).
+func GetFormatInfo(id string) (FormatInfo, bool) {
+	for _, info := range Formats {
+		if strings.EqualFold(string(info.ID), id) {
+			return info, true
+		}
+	}
+	return FormatInfo{}, false
+}
+
 // Legality represents a card's legality status in a format.
 type Legality struct {
 	Format       Format `json:"format"`
@@ -164,6 +218,9 @@ type Legality struct {
 	Banned       bool   `json:"banned,omitempty"`
 	Suspended    bool   `json:"suspended,omitempty"`
 	Restricted   bool   `json:"restricted,omitempty"`
+
+	// Reasons explains why the card is not legal, when it is not.
+	Reasons []LegalityReason `json:"reasons,omitempty"`
 }
 
 // GetLegality returns the legality information for a card in a given format.
@@ -226,16 +283,18 @@ func (c *Card) HasKeyword(keyword string) bool {
 	return slices.Contains(c.CardKeywords, keyword)
 }
 
+// classes are the types that are classes.
+var classes = map[string]bool{
+	"Generic": true, "Warrior": true, "Brute": true, "Guardian": true,
+	"Ninja": true, "Mechanologist": true, "Ranger": true, "Runeblade": true,
+	"Wizard": true, "Illusionist": true, "Elemental": true, "Light": true,
+	"Shadow": true, "Ice": true, "Lightning": true, "Earth": true,
+	"Mystic": true, "Assassin": true, "Shapeshifter": true, "Bard": true,
+	"Adjudicator": true, "Necromancer": true, "Draconic": true, "Royal": true,
+}
+
 // GetClass returns the class of the card (first type that is a class).
 func (c *Card) GetClass() string {
-	classes := map[string]bool{
-		"Generic": true, "Warrior": true, "Brute": true, "Guardian": true,
-		"Ninja": true, "Mechanologist": true, "Ranger": true, "Runeblade": true,
-		"Wizard": true, "Illusionist": true, "Elemental": true, "Light": true,
-		"Shadow": true, "Ice": true, "Lightning": true, "Earth": true,
-		"Mystic": true, "Assassin": true, "Shapeshifter": true, "Bard": true,
-		"Adjudicator": true, "Necromancer": true, "Draconic": true, "Royal": true,
-	}
 	for _, t := range c.Types {
 		if classes[t] {
 			return t
@@ -243,3 +302,122 @@ func (c *Card) GetClass() string {
 	}
 	return ""
 }
+
+// Classes returns every class of the card, for cards of several classes
+// such as "Ninja Warrior" attacks.
+func (c *Card) Classes() []string {
+	var result []string
+	for _, t := range c.Types {
+		if classes[t] {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// CardSummary is a compact view of a card for listings and cross-references.
+type CardSummary struct {
+	UniqueID string   `json:"unique_id"`
+	Name     string   `json:"name"`
+	Pitch    string   `json:"pitch,omitempty"`
+	Color    string   `json:"color,omitempty"`
+	TypeText string   `json:"type_text"`
+	Types    []string `json:"types"`
+}
+
+// Summary returns a compact view of the card.
+func (c *Card) Summary() CardSummary {
+	return CardSummary{
+		UniqueID: c.UniqueID,
+		Name:     c.Name,
+		Pitch:    c.Pitch,
+		Color:    c.Color,
+		TypeText: c.TypeText,
+		Types:    c.Types,
+	}
+}
+
+// CardReference is a directed edge from a card to a card it names, creates or
+// otherwise refers to in its text.
+type CardReference struct {
+	CardUniqueID           string `json:"card_unique_id"`
+	ReferencedCardUniqueID string `json:"referenced_card_unique_id"`
+}
+
+// GraphDirection selects which reference edges a card graph traversal follows.
+type GraphDirection string
+
+const (
+	GraphReferences   GraphDirection = "references"    // Cards this card refers to
+	GraphReferencedBy GraphDirection = "referenced_by" // Cards referring to this card
+	GraphBoth         GraphDirection = "both"
+)
+
+// CardGraphNode is a card reached during a graph traversal.
+type CardGraphNode struct {
+	CardSummary
+	Depth int `json:"depth"`
+}
+
+// CardGraph is the neighbourhood of a card in the reference graph.
+type CardGraph struct {
+	Root      string          `json:"root"`
+	Depth     int             `json:"depth"`
+	Direction GraphDirection  `json:"direction"`
+	Nodes     []CardGraphNode `json:"nodes"`
+	Edges     []CardReference `json:"edges"`
+}
+
+// FaceAssociation links the front and back faces of a card, as listed in
+// card-face-association.json. Face IDs are printing unique IDs.
+type FaceAssociation struct {
+	FrontUniqueID string `json:"front_unique_id"`
+	BackUniqueID  string `json:"back_unique_id"`
+	IsDFC         bool   `json:"is_DFC"`
+}
+
+// CardFaces is the chain of faces a card belongs to, resolved to cards. A chain
+// can branch when a face is associated with several others.
+type CardFaces struct {
+	Root  string            `json:"root"`
+	Faces []CardSummary     `json:"faces"`
+	Links []FaceAssociation `json:"links"`
+}
+
+// CardPrinting pairs a printing with the card it belongs to.
+type CardPrinting struct {
+	*Printing
+	Card  *Card          `json:"card"`
+	Names *PrintingNames `json:"names,omitempty"`
+}
+
+// PrintingNames holds the human-readable names of a printing's codes.
+type PrintingNames struct {
+	Set           string   `json:"set,omitempty"`
+	Edition       string   `json:"edition,omitempty"`
+	Foiling       string   `json:"foiling,omitempty"`
+	Rarity        string   `json:"rarity,omitempty"`
+	ArtVariations []string `json:"art_variations,omitempty"`
+}
+
+// Artist is an illustrator credited on card printings, with a breakdown of
+// their printings per set.
+type Artist struct {
+	Name          string           `json:"name"`
+	PrintingCount int              `json:"printing_count"`
+	CardCount     int              `json:"card_count"`
+	Sets          []ArtistSetCount `json:"sets"`
+}
+
+// ArtistSetCount is the number of printings an artist is credited on in a set.
+type ArtistSetCount struct {
+	SetID     string `json:"set_id"`
+	SetName   string `json:"set_name,omitempty"`
+	Printings int    `json:"printings"`
+}
+
+// Icon is a symbol used in card text, written as a token such as "{r}".
+type Icon struct {
+	Icon        string `json:"icon"`
+	Description string `json:"description"`
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
 	"strings"
 	"time"
 
@@ -14,6 +15,8 @@ import (
 	"github.com/oleiade/goagain/internal/data"
 	"github.com/oleiade/goagain/internal/domain"
 	"github.com/oleiade/goagain/internal/observability"
+	"github.com/oleiade/goagain/internal/query"
+	"github.com/oleiade/goagain/internal/render"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -23,17 +26,17 @@ import (
 // Server wraps the MCP server with card data access.
 type Server struct {
 	mcpServer *server.MCPServer
-	store     *data.Store
+	provider  *data.Provider
 	logger    *slog.Logger
 	metrics   *observability.Metrics
 }
 
 // NewServer creates a new MCP server with all tools registered.
-func NewServer(store *data.Store, logger *slog.Logger, metrics *observability.Metrics) *Server {
+func NewServer(provider *data.Provider, logger *slog.Logger, metrics *observability.Metrics) *Server {
 	s := &Server{
-		store:   store,
-		logger:  logger,
-		metrics: metrics,
+		provider: provider,
+		logger:   logger,
+		metrics:  metrics,
 	}
 
 	mcpServer := server.NewMCPServer(
@@ -49,9 +52,12 @@ func NewServer(store *data.Store, logger *slog.Logger, metrics *observability.Me
 	s.registerSearchSets(mcpServer)
 	s.registerGetSet(mcpServer)
 	s.registerSearchCardText(mcpServer)
+	s.registerQueryCards(mcpServer)
 	s.registerGetFormatLegality(mcpServer)
 	s.registerListKeywords(mcpServer)
 	s.registerGetKeyword(mcpServer)
+	s.registerGetFormatBanList(mcpServer)
+	s.registerSearchArtists(mcpServer)
 
 	s.mcpServer = mcpServer
 	return s
@@ -62,17 +68,34 @@ func (s *Server) MCPServer() *server.MCPServer {
 	return s.mcpServer
 }
 
+// storeKey is the context key for the store a tool invocation is served from.
+type storeKey struct{}
+
+// store returns the store the tool invocation in ctx is served from, so a
+// reload never changes the data under an invocation.
+func (s *Server) store(ctx context.Context) *data.Store {
+	if store, ok := ctx.Value(storeKey{}).(*data.Store); ok {
+		return store
+	}
+	return s.provider.Store()
+}
+
 // tracer is the OTel tracer for MCP tool invocations.
 var tracer = otel.Tracer("github.com/oleiade/goagain/mcp")
 
 // instrumentTool wraps a tool handler with tracing, metrics, and logging.
 func (s *Server) instrumentTool(toolName string, handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Serve the whole invocation from the current store
+		store := s.provider.Store()
+		ctx = context.WithValue(ctx, storeKey{}, store)
+
 		// Start a span for this tool invocation
 		ctx, span := tracer.Start(ctx, "mcp.tool."+toolName,
 			trace.WithSpanKind(trace.SpanKindInternal),
 			trace.WithAttributes(
 				attribute.String("mcp.tool.name", toolName),
+				attribute.String("goagain.data.version", store.Version),
 			),
 		)
 		defer span.End()
@@ -124,47 +147,108 @@ func (s *Server) instrumentTool(toolName string, handler func(ctx context.Contex
 	}
 }
 
+// statBounds are the stat range arguments of search_cards. The REST API also
+// takes _gte and _lte, which are synonyms of _min and _max left out here to
+// keep the tool schema short.
+var statBounds = []struct{ suffix, description string }{
+	{"min", "Minimum %s (inclusive)"},
+	{"max", "Maximum %s (inclusive)"},
+	{"gt", "Lower bound on %s (exclusive)"},
+	{"lt", "Upper bound on %s (exclusive)"},
+}
+
 func (s *Server) registerSearchCards(mcpServer *server.MCPServer) {
-	tool := mcp.NewTool("search_cards",
-		mcp.WithDescription("Search for Flesh and Blood cards by name, type, class, keywords, or other attributes"),
+	options := []mcp.ToolOption{
+		mcp.WithDescription("Search for Flesh and Blood cards by name, type, class, keywords, stat ranges or other attributes"),
 		mcp.WithString("name", mcp.Description("Filter by card name (partial match)")),
-		mcp.WithString("type", mcp.Description("Filter by card type (e.g., 'Action', 'Attack', 'Equipment')")),
-		mcp.WithString("class", mcp.Description("Filter by class (e.g., 'Warrior', 'Ninja', 'Wizard')")),
-		mcp.WithString("set", mcp.Description("Filter by set code (e.g., 'WTR', 'ARC', 'MON')")),
-		mcp.WithString("pitch", mcp.Description("Filter by pitch value ('1', '2', or '3')")),
-		mcp.WithString("keyword", mcp.Description("Filter by keyword (e.g., 'Go again', 'Dominate')")),
+		mcp.WithString("type", mcp.Description("Filter by card type, comma-separated for several (e.g., 'Action', 'Attack,Reaction')")),
+		mcp.WithString("class", mcp.Description("Filter by class, comma-separated for several (e.g., 'Warrior', 'Ninja,Warrior')")),
+		mcp.WithString("set", mcp.Description("Filter by set code, comma-separated for several (e.g., 'WTR', 'ARC,MON')")),
+		mcp.WithString("pitch", mcp.Description("Filter by pitch value, comma-separated for several ('1', '2', or '3')")),
+		mcp.WithString("keyword", mcp.Description("Filter by keyword (partial match), comma-separated for several (e.g., 'Go again,Dominate')")),
+		mcp.WithBoolean("all_faces", mcp.Description("List every face of multi-faced cards instead of one entry per card (default false)")),
+		mcp.WithBoolean("include_variable", mcp.Description("Let variable stats such as 'X' or '*' match any stat range (default false: they never match)")),
+		mcp.WithString("sort", mcp.Description("Sort by 'name', 'cost', 'power', 'defense', 'pitch', 'release_date' or 'relevance'")),
+		mcp.WithString("order", mcp.Description("Sort order, 'asc' or 'desc' (default asc, desc for relevance), with a sort")),
+		mcp.WithString("facets", mcp.Description("Count all matches by these comma-separated facets: 'class', 'type', 'pitch', 'keyword', 'set', 'rarity'")),
 		mcp.WithNumber("limit", mcp.Description("Maximum number of results (default 20, max 50)")),
-	)
+		mcp.WithString("cursor", mcp.Description("Cursor from a previous response's next_cursor or prev_cursor, to get the following or preceding page")),
+	}
+	for _, attribute := range data.CardAttributes {
+		name := string(attribute)
+		options = append(options,
+			mcp.WithString(name+"_mode", mcp.Description("Match 'any' (default) or 'all' of the "+name+" values")),
+			mcp.WithString("exclude_"+name, mcp.Description("Exclude cards with any of these comma-separated "+name+" values")),
+		)
+	}
+	for _, stat := range domain.StatNames {
+		for _, bound := range statBounds {
+			options = append(options, mcp.WithNumber(string(stat)+"_"+bound.suffix,
+				mcp.Description(fmt.Sprintf(bound.description, stat))))
+		}
+	}
+	tool := mcp.NewTool("search_cards", options...)
 
 	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := request.Params.Arguments
 
 		filter := data.CardFilter{
-			Name:    getStringArg(args, "name"),
-			Type:    getStringArg(args, "type"),
-			Class:   getStringArg(args, "class"),
-			SetID:   getStringArg(args, "set"),
-			Pitch:   getStringArg(args, "pitch"),
-			Keyword: getStringArg(args, "keyword"),
-			Limit:   getIntArg(args, "limit", 20),
+			Name:   getStringArg(args, "name"),
+			Limit:  getIntArg(args, "limit", 20),
+			Cursor: getStringArg(args, "cursor"),
+
+			CollapseFaces:   !getBoolArg(args, "all_faces"),
+			IncludeVariable: getBoolArg(args, "include_variable"),
+		}
+
+		if err := getValueFilterArgs(args, &filter); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		for _, stat := range domain.StatNames {
+			for _, bound := range statBounds {
+				if value, ok := getOptionalIntArg(args, string(stat)+"_"+bound.suffix); ok {
+					filter.SetStatBound(stat, bound.suffix, value)
+				}
+			}
+		}
+
+		if err := getCardSortArgs(args, &filter); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		facets, err := data.ParseFacets(getStringArg(args, "facets"))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		if filter.Limit > 50 {
 			filter.Limit = 50
 		}
 
-		cards, _ := s.store.SearchCards(filter)
+		page, err := s.store(ctx).SearchCardsPage(filter, facets)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		observability.SetCacheAttributes(ctx, observability.CacheCardSearch, page.Cached)
 
 		// Format results for display
 		var results []map[string]any
-		for _, card := range cards {
-			results = append(results, formatCardSummary(card))
+		for _, card := range page.Cards {
+			results = append(results, formatCardSummary(card.Card))
 		}
 
-		return mcp.NewToolResultText(formatJSON(map[string]any{
+		response := map[string]any{
 			"count":   len(results),
 			"results": results,
-		})), nil
+		}
+		if page.Facets != nil {
+			// Facets summarise every match, so the total goes with them
+			response["total"] = page.Total
+			response["facets"] = page.Facets
+		}
+		addPageCursors(response, page.Next, page.Prev)
+		return mcp.NewToolResultText(formatJSON(response)), nil
 	}
 
 	mcpServer.AddTool(tool, s.instrumentTool("search_cards", handler))
@@ -173,7 +257,10 @@ func (s *Server) registerSearchCards(mcpServer *server.MCPServer) {
 func (s *Server) registerGetCard(mcpServer *server.MCPServer) {
 	tool := mcp.NewTool("get_card",
 		mcp.WithDescription("Get full details of a specific Flesh and Blood card by unique ID or name"),
-		mcp.WithString("id", mcp.Required(), mcp.Description("The unique_id or exact name of the card")),
+		mcp.WithString("id", mcp.Required(), mcp.Description("The unique_id, printing ID (e.g., 'WTR001') or name of the card; misspelled names match the closest card")),
+		mcp.WithString("pitch", mcp.Description("Pitch of the variant to get when several cards share the name ('1', '2' or '3')")),
+		mcp.WithString("color", mcp.Description("Color of the variant to get when several cards share the name ('Red', 'Yellow' or 'Blue')")),
+		mcp.WithString("render", mcp.Description("How to render icons such as {r} in card text: 'plain' (words, default), 'markdown', 'html' or 'raw'")),
 	)
 
 	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -182,20 +269,30 @@ func (s *Server) registerGetCard(mcpServer *server.MCPServer) {
 			return mcp.NewToolResultError("id is required"), nil
 		}
 
-		card := s.store.GetCardByID(id)
-		if card == nil {
-			// Try by name
-			cards := s.store.GetCardsByName(id)
-			if len(cards) > 0 {
-				card = cards[0]
-			}
+		mode, err := getRenderArg(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		if card == nil {
-			return mcp.NewToolResultError(fmt.Sprintf("card not found: %s", id)), nil
+		card, fuzzy, failure := s.resolveCard(ctx, request.Params.Arguments, id)
+		if failure != nil {
+			return failure, nil
 		}
 
-		return mcp.NewToolResultText(formatJSON(formatCardFull(card))), nil
+		result := formatCardFull(card, s.renderText(ctx, card, mode))
+		if fuzzy {
+			result["matched_from"] = id
+		}
+
+		if faces := s.store(ctx).GetOtherFaces(card.UniqueID); len(faces) > 0 {
+			var summaries []map[string]any
+			for _, face := range faces {
+				summaries = append(summaries, formatCardSummary(face))
+			}
+			result["other_faces"] = summaries
+		}
+
+		return mcp.NewToolResultText(formatJSON(result)), nil
 	}
 
 	mcpServer.AddTool(tool, s.instrumentTool("get_card", handler))
@@ -208,7 +305,7 @@ func (s *Server) registerListSets(mcpServer *server.MCPServer) {
 
 	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var results []map[string]any
-		for _, set := range s.store.Sets {
+		for _, set := range s.store(ctx).Sets {
 			results = append(results, map[string]any{
 				"id":   set.ID,
 				"name": set.Name,
@@ -230,31 +327,50 @@ func (s *Server) registerSearchSets(mcpServer *server.MCPServer) {
 		mcp.WithString("name", mcp.Description("Filter by set name (partial match, case-insensitive)")),
 		mcp.WithString("id", mcp.Description("Filter by set code (partial match, case-insensitive)")),
 		mcp.WithString("q", mcp.Description("Search both name and code")),
+		mcp.WithString("sort", mcp.Description("Sort by 'release_date' or 'name' (default file order)")),
+		mcp.WithString("order", mcp.Description("Sort order, 'asc' (default) or 'desc', with a sort")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of results (default all)")),
+		mcp.WithString("cursor", mcp.Description("Cursor from a previous response's next_cursor or prev_cursor, to get the following or preceding page")),
 	)
 
 	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := request.Params.Arguments
 
 		filter := data.SetFilter{
-			Name:  getStringArg(args, "name"),
-			ID:    getStringArg(args, "id"),
-			Query: getStringArg(args, "q"),
+			Name:   getStringArg(args, "name"),
+			ID:     getStringArg(args, "id"),
+			Query:  getStringArg(args, "q"),
+			Limit:  getIntArg(args, "limit", 0),
+			Cursor: getStringArg(args, "cursor"),
+		}
+
+		var err error
+		if filter.Sort, err = data.ParseSetSort(getStringArg(args, "sort")); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if filter.Order, err = data.ParseSetOrder(filter.Sort, getStringArg(args, "order")); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		sets := s.store.SearchSets(filter)
+		page, err := s.store(ctx).SearchSetsPage(filter)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 
 		var results []map[string]any
-		for _, set := range sets {
+		for _, set := range page.Sets {
 			results = append(results, map[string]any{
 				"id":   set.ID,
 				"name": set.Name,
 			})
 		}
 
-		return mcp.NewToolResultText(formatJSON(map[string]any{
+		response := map[string]any{
 			"count": len(results),
 			"sets":  results,
-		})), nil
+		}
+		addPageCursors(response, page.Next, page.Prev)
+		return mcp.NewToolResultText(formatJSON(response)), nil
 	}
 
 	mcpServer.AddTool(tool, s.instrumentTool("search_sets", handler))
@@ -273,7 +389,7 @@ func (s *Server) registerGetSet(mcpServer *server.MCPServer) {
 			return mcp.NewToolResultError("id is required"), nil
 		}
 
-		set := s.store.GetSetByID(id)
+		set := s.store(ctx).GetSetByID(id)
 		if set == nil {
 			return mcp.NewToolResultError(fmt.Sprintf("set not found: %s", id)), nil
 		}
@@ -285,7 +401,7 @@ func (s *Server) registerGetSet(mcpServer *server.MCPServer) {
 		}
 
 		if getBoolArg(request.Params.Arguments, "include_cards") {
-			cards := s.store.GetCardsInSet(id)
+			cards := s.store(ctx).GetCardsInSet(id)
 			var cardSummaries []map[string]any
 			for _, card := range cards {
 				cardSummaries = append(cardSummaries, formatCardSummary(card))
@@ -302,9 +418,13 @@ func (s *Server) registerGetSet(mcpServer *server.MCPServer) {
 
 func (s *Server) registerSearchCardText(mcpServer *server.MCPServer) {
 	tool := mcp.NewTool("search_card_text",
-		mcp.WithDescription("Search for cards by text in their abilities or effects"),
-		mcp.WithString("query", mcp.Required(), mcp.Description("Text to search for in card abilities/effects")),
+		mcp.WithDescription("Full-text search over card names, type lines and abilities, ranked by relevance"),
+		mcp.WithString("query", mcp.Required(), mcp.Description("Words to search for; every word must match. Use \"quotes\" for an exact phrase and a trailing * for a prefix (e.g., 'draw*')")),
+		mcp.WithString("render", mcp.Description("How to render icons such as {r} in card text: 'plain' (words, default), 'markdown', 'html' or 'raw'")),
+		mcp.WithString("sort", mcp.Description("Sort by 'name', 'cost', 'power', 'defense', 'pitch', 'release_date' or 'relevance'")),
+		mcp.WithString("order", mcp.Description("Sort order, 'asc' or 'desc' (default asc, desc for relevance), with a sort")),
 		mcp.WithNumber("limit", mcp.Description("Maximum number of results (default 20, max 50)")),
+		mcp.WithString("cursor", mcp.Description("Cursor from a previous response's next_cursor or prev_cursor, to get the following or preceding page")),
 	)
 
 	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -313,36 +433,121 @@ func (s *Server) registerSearchCardText(mcpServer *server.MCPServer) {
 			return mcp.NewToolResultError("query is required"), nil
 		}
 
+		mode, err := getRenderArg(request.Params.Arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		filter := data.CardFilter{
-			TextQuery: query,
-			Limit:     getIntArg(request.Params.Arguments, "limit", 20),
+			TextQuery:     query,
+			Limit:         getIntArg(request.Params.Arguments, "limit", 20),
+			Cursor:        getStringArg(request.Params.Arguments, "cursor"),
+			CollapseFaces: true,
+		}
+
+		if err := getCardSortArgs(request.Params.Arguments, &filter); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		if filter.Limit > 50 {
 			filter.Limit = 50
 		}
 
-		cards, _ := s.store.SearchCards(filter)
+		page, err := s.store(ctx).SearchCardsPage(filter, nil)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		observability.SetCacheAttributes(ctx, observability.CacheCardSearch, page.Cached)
 
 		var results []map[string]any
-		for _, card := range cards {
-			results = append(results, formatCardSummary(card))
+		for _, card := range page.Cards {
+			result := formatCardSummary(card.Card)
+			result["score"] = math.Round(card.Score*1000) / 1000
+			result["functional_text"] = s.renderText(ctx, card.Card, mode)
+			results = append(results, result)
 		}
 
-		return mcp.NewToolResultText(formatJSON(map[string]any{
+		response := map[string]any{
 			"query":   query,
 			"count":   len(results),
 			"results": results,
-		})), nil
+		}
+		addPageCursors(response, page.Next, page.Prev)
+		return mcp.NewToolResultText(formatJSON(response)), nil
 	}
 
 	mcpServer.AddTool(tool, s.instrumentTool("search_card_text", handler))
 }
 
+func (s *Server) registerQueryCards(mcpServer *server.MCPServer) {
+	tool := mcp.NewTool("query_cards",
+		mcp.WithDescription("Search cards with a Scryfall-style query language. Terms are ANDed; use 'or', parentheses, and '-' or 'not' to negate. "+
+			"Fields: name (n), class (c), type (t), keyword (kw), text (o), set (s), rarity (r), artist (a), color, "+
+			"pitch (p), cost, power (pow), defense (def), health (hp), intelligence (int), arcane, and the formats legal (f), banned, suspended, restricted. "+
+			"Text fields take ':' (contains), '=' or '!='; numeric fields also take <, <=, > and >=. Bare words match card names."),
+		mcp.WithString("query", mcp.Required(), mcp.Description("The query, e.g. 'c:ninja t:attack pow>=4 kw:\"go again\" -legal:blitz'")),
+		mcp.WithBoolean("all_faces", mcp.Description("List every face of multi-faced cards instead of one entry per card (default false)")),
+		mcp.WithString("sort", mcp.Description("Sort by 'name', 'cost', 'power', 'defense', 'pitch', 'release_date' or 'relevance'")),
+		mcp.WithString("order", mcp.Description("Sort order, 'asc' or 'desc' (default asc, desc for relevance), with a sort")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of results (default 20, max 50)")),
+		mcp.WithString("cursor", mcp.Description("Cursor from a previous response's next_cursor or prev_cursor, to get the following or preceding page")),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.Params.Arguments
+
+		expr, err := query.Parse(getStringArg(args, "query"))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid query: %v", err)), nil
+		}
+
+		filter := data.CardFilter{
+			Query:  expr,
+			Limit:  getIntArg(args, "limit", 20),
+			Cursor: getStringArg(args, "cursor"),
+
+			CollapseFaces: !getBoolArg(args, "all_faces"),
+		}
+
+		if err := getCardSortArgs(args, &filter); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if filter.Limit > 50 {
+			filter.Limit = 50
+		}
+
+		page, err := s.store(ctx).SearchCardsPage(filter, nil)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		observability.SetCacheAttributes(ctx, observability.CacheCardSearch, page.Cached)
+
+		var results []map[string]any
+		for _, card := range page.Cards {
+			results = append(results, formatCardSummary(card.Card))
+		}
+
+		response := map[string]any{
+			"query":   expr.String(),
+			"total":   page.Total,
+			"count":   len(results),
+			"results": results,
+		}
+		addPageCursors(response, page.Next, page.Prev)
+		return mcp.NewToolResultText(formatJSON(response)), nil
+	}
+
+	mcpServer.AddTool(tool, s.instrumentTool("query_cards", handler))
+}
+
 func (s *Server) registerGetFormatLegality(mcpServer *server.MCPServer) {
 	tool := mcp.NewTool("get_format_legality",
-		mcp.WithDescription("Check a card's legality status across all formats"),
-		mcp.WithString("id", mcp.Required(), mcp.Description("The unique_id or name of the card")),
+		mcp.WithDescription("Check a card's legality status across all formats, with the dated history of bans, suspensions, living legend and restriction announcements"),
+		mcp.WithString("id", mcp.Required(), mcp.Description("The unique_id, printing ID (e.g., 'WTR001') or name of the card")),
+		mcp.WithString("pitch", mcp.Description("Pitch of the variant to check when several cards share the name ('1', '2' or '3')")),
+		mcp.WithString("color", mcp.Description("Color of the variant to check when several cards share the name ('Red', 'Yellow' or 'Blue')")),
+		mcp.WithString("as_of", mcp.Description("Check legality as of this date (YYYY-MM-DD) instead of today")),
 	)
 
 	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -351,16 +556,18 @@ func (s *Server) registerGetFormatLegality(mcpServer *server.MCPServer) {
 			return mcp.NewToolResultError("id is required"), nil
 		}
 
-		card := s.store.GetCardByID(id)
-		if card == nil {
-			cards := s.store.GetCardsByName(id)
-			if len(cards) > 0 {
-				card = cards[0]
+		var asOf time.Time
+		if value := getStringArg(request.Params.Arguments, "as_of"); value != "" {
+			var err error
+			asOf, err = domain.ParseDate(value)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid as_of date %q, expected YYYY-MM-DD", value)), nil
 			}
 		}
 
-		if card == nil {
-			return mcp.NewToolResultError(fmt.Sprintf("card not found: %s", id)), nil
+		card, _, failure := s.resolveCard(ctx, request.Params.Arguments, id)
+		if failure != nil {
+			return failure, nil
 		}
 
 		formats := []domain.Format{
@@ -374,21 +581,40 @@ func (s *Server) registerGetFormatLegality(mcpServer *server.MCPServer) {
 
 		legalities := make(map[string]any)
 		for _, format := range formats {
-			leg := card.GetLegality(format)
-			legalities[string(format)] = map[string]any{
+			var leg domain.Legality
+			if asOf.IsZero() {
+				leg = s.store(ctx).GetLegality(card, format)
+			} else {
+				leg = s.store(ctx).GetLegalityAt(card, format, asOf)
+			}
+			entry := map[string]any{
 				"legal":         leg.Legal,
 				"living_legend": leg.LivingLegend,
 				"banned":        leg.Banned,
 				"suspended":     leg.Suspended,
 				"restricted":    leg.Restricted,
 			}
+			if len(leg.Reasons) > 0 {
+				entry["reasons"] = formatLegalityReasons(leg.Reasons)
+			}
+			legalities[string(format)] = entry
 		}
 
-		return mcp.NewToolResultText(formatJSON(map[string]any{
+		result := map[string]any{
 			"card_id":    card.UniqueID,
 			"card_name":  card.Name,
 			"legalities": legalities,
-		})), nil
+		}
+
+		if !asOf.IsZero() {
+			result["as_of"] = asOf.Format(time.DateOnly)
+		}
+
+		if history := s.store(ctx).GetLegalityHistory(card.UniqueID); len(history) > 0 {
+			result["history"] = formatLegalityHistory(history)
+		}
+
+		return mcp.NewToolResultText(formatJSON(result)), nil
 	}
 
 	mcpServer.AddTool(tool, s.instrumentTool("get_format_legality", handler))
@@ -401,7 +627,7 @@ func (s *Server) registerListKeywords(mcpServer *server.MCPServer) {
 
 	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var results []map[string]any
-		for _, kw := range s.store.Keywords {
+		for _, kw := range s.store(ctx).Keywords {
 			results = append(results, map[string]any{
 				"name":        kw.Name,
 				"description": kw.DescriptionPlain,
@@ -429,7 +655,7 @@ func (s *Server) registerGetKeyword(mcpServer *server.MCPServer) {
 			return mcp.NewToolResultError("name is required"), nil
 		}
 
-		kw := s.store.GetKeywordByName(name)
+		kw := s.store(ctx).GetKeywordByName(name)
 		if kw == nil {
 			return mcp.NewToolResultError(fmt.Sprintf("keyword not found: %s", name)), nil
 		}
@@ -443,6 +669,121 @@ func (s *Server) registerGetKeyword(mcpServer *server.MCPServer) {
 	mcpServer.AddTool(tool, s.instrumentTool("get_keyword", handler))
 }
 
+func (s *Server) registerGetFormatBanList(mcpServer *server.MCPServer) {
+	tool := mcp.NewTool("get_format_ban_list",
+		mcp.WithDescription("List the cards currently banned, suspended, living legend or restricted in a format, with the date and announcement for each"),
+		mcp.WithString("format", mcp.Required(), mcp.Description("The format ('blitz', 'cc', 'commoner', 'll', 'silver_age', or 'upf')")),
+		mcp.WithString("as_of", mcp.Description("Show the ban list as of this date (YYYY-MM-DD) instead of today")),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id := getStringArg(request.Params.Arguments, "format")
+		if id == "" {
+			return mcp.NewToolResultError("format is required"), nil
+		}
+
+		format, ok := domain.GetFormatInfo(id)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("format not found: %s", id)), nil
+		}
+
+		asOf := time.Now().UTC()
+		if value := getStringArg(request.Params.Arguments, "as_of"); value != "" {
+			var err error
+			asOf, err = domain.ParseDate(value)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid as_of date %q, expected YYYY-MM-DD", value)), nil
+			}
+		}
+
+		banList := s.store(ctx).GetBanList(format, asOf)
+
+		return mcp.NewToolResultText(formatJSON(map[string]any{
+			"format":        banList.ID,
+			"name":          banList.Name,
+			"as_of":         banList.AsOf,
+			"banned":        formatBanListEntries(banList.Banned),
+			"suspended":     formatBanListEntries(banList.Suspended),
+			"living_legend": formatBanListEntries(banList.LivingLegend),
+			"restricted":    formatBanListEntries(banList.Restricted),
+		})), nil
+	}
+
+	mcpServer.AddTool(tool, s.instrumentTool("get_format_ban_list", handler))
+}
+
+func (s *Server) registerSearchArtists(mcpServer *server.MCPServer) {
+	tool := mcp.NewTool("search_artists",
+		mcp.WithDescription("Search Flesh and Blood card artists by name, with their printing counts per set. When a single artist matches, their printings can be listed too"),
+		mcp.WithString("name", mcp.Description("Filter by artist name (partial match, case-insensitive)")),
+		mcp.WithBoolean("include_printings", mcp.Description("List the printings of the artist when exactly one artist matches (default false)")),
+		mcp.WithString("set", mcp.Description("Only list printings from this set code (e.g., 'WTR')")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of artists or printings to return (default 20, max 50)")),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.Params.Arguments
+
+		limit := getIntArg(args, "limit", 20)
+		if limit > 50 {
+			limit = 50
+		}
+
+		// Prefer an exact match so "include_printings" works for artists whose
+		// name is contained in another's.
+		name := getStringArg(args, "name")
+		artists := s.store(ctx).SearchArtists(name)
+		if artist := s.store(ctx).GetArtist(name); artist != nil {
+			artists = []*domain.Artist{artist}
+		}
+
+		total := len(artists)
+		if len(artists) > limit {
+			artists = artists[:limit]
+		}
+
+		var results []map[string]any
+		for _, artist := range artists {
+			results = append(results, map[string]any{
+				"name":           artist.Name,
+				"printing_count": artist.PrintingCount,
+				"card_count":     artist.CardCount,
+				"sets":           artist.Sets,
+			})
+		}
+
+		result := map[string]any{
+			"count":   len(results),
+			"total":   total,
+			"artists": results,
+		}
+
+		if total == 1 && getBoolArg(args, "include_printings") {
+			printings, printingTotal := s.store(ctx).GetArtistPrintings(artists[0].Name, data.ArtistPrintingFilter{
+				SetID: getStringArg(args, "set"),
+				Limit: limit,
+			})
+
+			var printingResults []map[string]any
+			for _, printing := range printings {
+				printingResults = append(printingResults, map[string]any{
+					"id":        printing.ID,
+					"set":       printing.SetID,
+					"card_id":   printing.Card.UniqueID,
+					"card_name": printing.Card.Name,
+					"pitch":     printing.Card.Pitch,
+				})
+			}
+			result["printings"] = printingResults
+			result["printings_total"] = printingTotal
+		}
+
+		return mcp.NewToolResultText(formatJSON(result)), nil
+	}
+
+	mcpServer.AddTool(tool, s.instrumentTool("search_artists", handler))
+}
+
 // Helper functions
 
 func getStringArg(args any, key string) string {
@@ -474,6 +815,71 @@ func getIntArg(args any, key string, defaultVal int) int {
 	return defaultVal
 }
 
+// getValueFilterArgs parses the comma-separated class, type, keyword, set and
+// pitch arguments, with their <name>_mode and exclude_<name> companions.
+func getValueFilterArgs(args any, filter *data.CardFilter) error {
+	for _, attribute := range data.CardAttributes {
+		name := string(attribute)
+		mode, err := data.ParseMatchMode(getStringArg(args, name+"_mode"))
+		if err != nil {
+			return fmt.Errorf("invalid %s_mode %q, expected any or all", name, getStringArg(args, name+"_mode"))
+		}
+
+		if values := data.SplitValues(getStringArg(args, name)); len(values) > 0 {
+			filter.Values = append(filter.Values, data.ValueFilter{
+				Attribute: attribute,
+				Values:    values,
+				Mode:      mode,
+			})
+		}
+		if values := data.SplitValues(getStringArg(args, "exclude_"+name)); len(values) > 0 {
+			filter.Values = append(filter.Values, data.ValueFilter{
+				Attribute: attribute,
+				Values:    values,
+				Negate:    true,
+			})
+		}
+	}
+	return nil
+}
+
+// addPageCursors adds the cursors to the neighbouring pages to a search
+// response.
+func addPageCursors(response map[string]any, next, prev string) {
+	if next != "" {
+		response["next_cursor"] = next
+	}
+	if prev != "" {
+		response["prev_cursor"] = prev
+	}
+}
+
+// getCardSortArgs parses the sort and order arguments of card searches.
+func getCardSortArgs(args any, filter *data.CardFilter) error {
+	var err error
+	if filter.Sort, err = data.ParseCardSort(getStringArg(args, "sort")); err != nil {
+		return err
+	}
+	filter.Order, err = data.ParseCardOrder(filter.Sort, getStringArg(args, "order"))
+	return err
+}
+
+// getOptionalIntArg returns a numeric argument and whether it was given.
+func getOptionalIntArg(args any, key string) (int, bool) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return 0, false
+	}
+	switch n := m[key].(type) {
+	case float64:
+		return int(n), true
+	case int:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
 func getBoolArg(args any, key string) bool {
 	m, ok := args.(map[string]any)
 	if !ok {
@@ -492,6 +898,36 @@ func formatJSON(v any) string {
 	return string(b)
 }
 
+// resolveCard resolves a tool's card reference, narrowed by its pitch and
+// color arguments. When the reference matches no card, or several, it returns
+// the error result listing suggestions or candidates instead. It also reports
+// whether the name match was inexact.
+func (s *Server) resolveCard(ctx context.Context, args any, id string) (*domain.Card, bool, *mcp.CallToolResult) {
+	match := s.store(ctx).ResolveCard(id, getStringArg(args, "pitch"), getStringArg(args, "color"))
+
+	switch {
+	case match.Card != nil:
+		return match.Card, match.Fuzzy, nil
+
+	case len(match.Candidates) > 0:
+		var candidates []map[string]any
+		for _, card := range match.Candidates {
+			candidates = append(candidates, formatCardSummary(card))
+		}
+		return nil, false, mcp.NewToolResultError(formatJSON(map[string]any{
+			"error":      fmt.Sprintf("%d cards are named %q; call again with pitch or color to choose one", len(candidates), match.Candidates[0].Name),
+			"candidates": candidates,
+		}))
+
+	default:
+		message := fmt.Sprintf("card not found: %s", id)
+		if suggestions := s.store(ctx).SuggestCardNames(id, 5); len(suggestions) > 0 {
+			message += fmt.Sprintf(". Did you mean: %s?", strings.Join(suggestions, ", "))
+		}
+		return nil, false, mcp.NewToolResultError(message)
+	}
+}
+
 func formatCardSummary(card *domain.Card) map[string]any {
 	result := map[string]any{
 		"unique_id": card.UniqueID,
@@ -502,6 +938,9 @@ func formatCardSummary(card *domain.Card) map[string]any {
 	if card.Pitch != "" {
 		result["pitch"] = card.Pitch
 	}
+	if card.Color != "" {
+		result["color"] = card.Color
+	}
 	if card.Cost != "" {
 		result["cost"] = card.Cost
 	}
@@ -515,13 +954,31 @@ func formatCardSummary(card *domain.Card) map[string]any {
 	return result
 }
 
-func formatCardFull(card *domain.Card) map[string]any {
+// getRenderArg parses the render argument. Tools default to plain text, as
+// raw icon tokens such as {r} are easily misread.
+func getRenderArg(args any) (render.Mode, error) {
+	value := getStringArg(args, "render")
+	if value == "" {
+		return render.ModePlain, nil
+	}
+	return render.ParseMode(value)
+}
+
+// renderText renders a card's functional text in the given mode.
+func (s *Server) renderText(ctx context.Context, card *domain.Card, mode render.Mode) string {
+	if mode == render.ModeRaw {
+		return card.FunctionalTextPlain
+	}
+	return s.store(ctx).RenderText(card.FunctionalText, card.FunctionalTextPlain, mode)
+}
+
+func formatCardFull(card *domain.Card, text string) map[string]any {
 	result := map[string]any{
 		"unique_id":       card.UniqueID,
 		"name":            card.Name,
 		"type_text":       card.TypeText,
 		"types":           card.Types,
-		"functional_text": card.FunctionalTextPlain,
+		"functional_text": text,
 	}
 
 	if card.Color != "" {
@@ -572,3 +1029,68 @@ func formatCardFull(card *domain.Card) map[string]any {
 
 	return result
 }
+
+func formatLegalityHistory(history domain.LegalityHistory) map[string]any {
+	result := make(map[string]any, len(history))
+	for format, timeline := range history {
+		var events []map[string]any
+		for _, event := range timeline {
+			entry := map[string]any{
+				"status":         event.Status,
+				"active":         event.Active,
+				"date_announced": event.DateAnnounced.Format(time.DateOnly),
+				"date_in_effect": event.DateInEffect.Format(time.DateOnly),
+				"article":        event.LegalityArticle,
+			}
+			if event.PlannedEnd != "" {
+				entry["planned_end"] = event.PlannedEnd
+			}
+			events = append(events, entry)
+		}
+		result[string(format)] = events
+	}
+	return result
+}
+
+func formatBanListEntries(entries []domain.BanListEntry) []map[string]any {
+	results := make([]map[string]any, 0, len(entries))
+	for _, entry := range entries {
+		result := map[string]any{
+			"card_id":        entry.CardUniqueID,
+			"name":           entry.CardName,
+			"date_in_effect": entry.DateInEffect.Format(time.DateOnly),
+			"article":        entry.LegalityArticle,
+		}
+		if entry.Pitch != "" {
+			result["pitch"] = entry.Pitch
+		}
+		if entry.PlannedEnd != "" {
+			result["planned_end"] = entry.PlannedEnd
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func formatLegalityReasons(reasons []domain.LegalityReason) []map[string]any {
+	results := make([]map[string]any, 0, len(reasons))
+	for _, reason := range reasons {
+		result := map[string]any{
+			"code": reason.Code,
+		}
+		if reason.Start != nil {
+			result["start"] = reason.Start.Format(time.DateOnly)
+		}
+		if reason.End != nil {
+			result["end"] = reason.End.Format(time.DateOnly)
+		}
+		if reason.PlannedEnd != "" {
+			result["planned_end"] = reason.PlannedEnd
+		}
+		if reason.Article != "" {
+			result["article"] = reason.Article
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -10,6 +10,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const meterName = "github.com/oleiade/goagain"
@@ -33,6 +34,11 @@ type Metrics struct {
 	mcpSessionsTotal        metric.Int64Counter
 	mcpSessionsActive       metric.Int64UpDownCounter
 
+	// Cache metrics
+	cacheHits      metric.Int64Counter
+	cacheMisses    metric.Int64Counter
+	cacheEvictions metric.Int64Counter
+
 	// Application metrics (using callbacks for gauges)
 	dataCardsTotal     int64
 	dataSetsTotal      int64
@@ -148,6 +154,31 @@ func NewMetrics(serviceName string) *Metrics {
 		otel.Handle(err)
 	}
 
+	// Cache metrics
+	m.cacheHits, err = meter.Int64Counter("goagain.cache.hits",
+		metric.WithDescription("Total number of lookups served from a cache"),
+		metric.WithUnit("{lookup}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	m.cacheMisses, err = meter.Int64Counter("goagain.cache.misses",
+		metric.WithDescription("Total number of lookups not found in a cache"),
+		metric.WithUnit("{lookup}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	m.cacheEvictions, err = meter.Int64Counter("goagain.cache.evictions",
+		metric.WithDescription("Total number of entries dropped from a cache, when full or invalidated"),
+		metric.WithUnit("{entry}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
 	// Register async gauges for application data metrics
 	m.registerDataGauges()
 
@@ -338,6 +369,38 @@ func (m *Metrics) ToolInFlightDec(toolName string) {
 	m.mcpToolInFlight.Add(context.Background(), -1, metric.WithAttributes(attribute.String("tool.name", toolName)))
 }
 
+// CacheCardSearch names the card search result cache in cache metrics and
+// span attributes.
+const CacheCardSearch = "card_search"
+
+// RecordCacheHit records a lookup served from a cache.
+func (m *Metrics) RecordCacheHit(cache string) {
+	m.cacheHits.Add(context.Background(), 1, metric.WithAttributes(attribute.String("cache.name", cache)))
+}
+
+// RecordCacheMiss records a lookup not found in a cache.
+func (m *Metrics) RecordCacheMiss(cache string) {
+	m.cacheMisses.Add(context.Background(), 1, metric.WithAttributes(attribute.String("cache.name", cache)))
+}
+
+// RecordCacheEvictions records entries dropped from a cache, for a reason
+// such as "capacity" or "invalidated".
+func (m *Metrics) RecordCacheEvictions(cache string, count int, reason string) {
+	m.cacheEvictions.Add(context.Background(), int64(count), metric.WithAttributes(
+		attribute.String("cache.name", cache),
+		attribute.String("cache.eviction_reason", reason),
+	))
+}
+
+// SetCacheAttributes records on the current span whether a cache served the
+// lookup.
+func SetCacheAttributes(ctx context.Context, cache string, hit bool) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("goagain.cache.name", cache),
+		attribute.Bool("goagain.cache.hit", hit),
+	)
+}
+
 // RecordSessionStart records a new MCP session.
 func (m *Metrics) RecordSessionStart() {
 	ctx := context.Background()
@@ -358,14 +421,31 @@ func PathNormalizer() func(string) string {
 		pattern *regexp.Regexp
 		replace string
 	}{
+		// /v1/cards/search and /v1/cards/autocomplete, which would otherwise look like card IDs
+		{regexp.MustCompile(`^/v1/cards/search$`), "/v1/cards/search"},
+		{regexp.MustCompile(`^/v1/cards/autocomplete$`), "/v1/cards/autocomplete"},
 		// /v1/cards/{id} - card unique IDs
 		{regexp.MustCompile(`^/v1/cards/[^/]+$`), "/v1/cards/{id}"},
 		// /v1/cards/{id}/legality
 		{regexp.MustCompile(`^/v1/cards/[^/]+/legality$`), "/v1/cards/{id}/legality"},
+		// /v1/cards/{id}/references, /v1/cards/{id}/referenced-by, /v1/cards/{id}/graph
+		{regexp.MustCompile(`^/v1/cards/[^/]+/references$`), "/v1/cards/{id}/references"},
+		{regexp.MustCompile(`^/v1/cards/[^/]+/referenced-by$`), "/v1/cards/{id}/referenced-by"},
+		{regexp.MustCompile(`^/v1/cards/[^/]+/graph$`), "/v1/cards/{id}/graph"},
+		// /v1/cards/{id}/faces
+		{regexp.MustCompile(`^/v1/cards/[^/]+/faces$`), "/v1/cards/{id}/faces"},
+		// /v1/printings/{id}
+		{regexp.MustCompile(`^/v1/printings/[^/]+$`), "/v1/printings/{id}"},
+		// /v1/artists/{name}/printings
+		{regexp.MustCompile(`^/v1/artists/[^/]+/printings$`), "/v1/artists/{name}/printings"},
+		// /v1/artists/{name}
+		{regexp.MustCompile(`^/v1/artists/[^/]+$`), "/v1/artists/{name}"},
 		// /v1/sets/{id}
 		{regexp.MustCompile(`^/v1/sets/[^/]+$`), "/v1/sets/{id}"},
 		// /v1/keywords/{name}
 		{regexp.MustCompile(`^/v1/keywords/[^/]+$`), "/v1/keywords/{name}"},
+		// /v1/formats/{format}
+		{regexp.MustCompile(`^/v1/formats/[^/]+$`), "/v1/formats/{format}"},
 	}
 
 	return func(path string) string {
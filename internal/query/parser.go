@@ -0,0 +1,349 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenNot   // "-" prefix
+	tokenWord  // bare word, including "and", "or" and "not"
+	tokenQuote // bare "quoted string"
+	tokenTerm  // field, operator and value
+)
+
+type token struct {
+	kind tokenKind
+	text string // the token as written
+	pos  int
+
+	// Term tokens only
+	field    string
+	op       Operator
+	value    string
+	valuePos int
+}
+
+// operators lists the comparison operators, longest first.
+var operators = []Operator{OpNotEqual, OpLessEqual, OpGreaterEqual, OpMatch, OpEqual, OpLess, OpGreater}
+
+func isSpace(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' }
+
+func isOperatorStart(c byte) bool { return strings.IndexByte(":=!<>", c) >= 0 }
+
+// lex splits a query into tokens.
+func lex(input string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case isSpace(c):
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+
+		case c == '-' && i+1 < len(input) && !isSpace(input[i+1]):
+			tokens = append(tokens, token{kind: tokenNot, text: "-", pos: i})
+			i++
+
+		case c == '"':
+			value, end, err := lexQuoted(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenQuote, text: input[i:end], pos: i, value: value})
+			i = end
+
+		case isOperatorStart(c):
+			end := lexWordEnd(input, i)
+			return nil, &SyntaxError{Message: "missing field name before operator", Pos: i, Token: input[i:end]}
+
+		default:
+			start := i
+			for i < len(input) && !isSpace(input[i]) && !isOperatorStart(input[i]) &&
+				input[i] != '(' && input[i] != ')' && input[i] != '"' {
+				i++
+			}
+
+			if i == len(input) || !isOperatorStart(input[i]) {
+				tokens = append(tokens, token{kind: tokenWord, text: input[start:i], pos: start, value: input[start:i]})
+				continue
+			}
+
+			tok, end, err := lexTerm(input, start, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = end
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd, pos: len(input)}), nil
+}
+
+// lexTerm reads the operator and value of a term whose field ends at opPos.
+func lexTerm(input string, start, opPos int) (token, int, error) {
+	tok := token{kind: tokenTerm, pos: start, field: input[start:opPos]}
+
+	for _, op := range operators {
+		if strings.HasPrefix(input[opPos:], string(op)) {
+			tok.op = op
+			break
+		}
+	}
+	if tok.op == "" {
+		end := lexWordEnd(input, opPos)
+		return token{}, 0, &SyntaxError{Message: "invalid operator", Pos: opPos, Token: input[opPos:end]}
+	}
+
+	i := opPos + len(tok.op)
+	tok.valuePos = i
+
+	if i < len(input) && input[i] == '"' {
+		value, end, err := lexQuoted(input, i)
+		if err != nil {
+			return token{}, 0, err
+		}
+		tok.value = value
+		i = end
+	} else {
+		end := lexWordEnd(input, i)
+		if end == i {
+			return token{}, 0, &SyntaxError{Message: "missing value after operator", Pos: start, Token: input[start:i]}
+		}
+		tok.value = input[i:end]
+		i = end
+	}
+
+	tok.text = input[start:i]
+	return tok, i, nil
+}
+
+// lexQuoted reads the string starting with the quote at start, returning its
+// contents and the offset after the closing quote.
+func lexQuoted(input string, start int) (string, int, error) {
+	end := strings.IndexByte(input[start+1:], '"')
+	if end < 0 {
+		return "", 0, &SyntaxError{Message: "unterminated quoted string", Pos: start, Token: input[start:]}
+	}
+	return input[start+1 : start+1+end], start + end + 2, nil
+}
+
+func lexWordEnd(input string, i int) int {
+	for i < len(input) && !isSpace(input[i]) && input[i] != '(' && input[i] != ')' {
+		i++
+	}
+	return i
+}
+
+// Parse parses a query into its syntax tree. Errors are *SyntaxError values
+// pointing at the offending token.
+func Parse(input string) (Node, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEnd {
+		return nil, &SyntaxError{Message: "query is empty"}
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEnd {
+		return nil, p.unexpected(tok)
+	}
+	return node, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEnd {
+		p.pos++
+	}
+	return tok
+}
+
+// isKeyword reports whether the token is the bare boolean keyword word.
+func isKeyword(tok token, word string) bool {
+	return tok.kind == tokenWord && strings.EqualFold(tok.text, word)
+}
+
+func (p *parser) unexpected(tok token) error {
+	if tok.kind == tokenEnd {
+		return &SyntaxError{Message: "unexpected end of query", Pos: tok.pos}
+	}
+	return &SyntaxError{Message: "unexpected token", Pos: tok.pos, Token: tok.text}
+}
+
+// parseOr parses: and ("or" and)*
+func (p *parser) parseOr() (Node, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{node}
+	for isKeyword(p.peek(), "or") {
+		p.next()
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return &Or{Nodes: nodes}, nil
+}
+
+// parseAnd parses: unary (["and"] unary)*
+func (p *parser) parseAnd() (Node, error) {
+	var nodes []Node
+
+	for {
+		tok := p.peek()
+		if tok.kind == tokenEnd || tok.kind == tokenRParen || isKeyword(tok, "or") {
+			if len(nodes) == 0 || isKeyword(p.tokens[max(p.pos-1, 0)], "and") {
+				return nil, p.unexpected(tok)
+			}
+			break
+		}
+		if isKeyword(tok, "and") {
+			if len(nodes) == 0 {
+				return nil, p.unexpected(tok)
+			}
+			p.next()
+			continue
+		}
+
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return &And{Nodes: nodes}, nil
+}
+
+// parseUnary parses: ("-" | "not") unary | primary
+func (p *parser) parseUnary() (Node, error) {
+	if tok := p.peek(); tok.kind == tokenNot || isKeyword(tok, "not") {
+		p.next()
+		if next := p.peek(); next.kind == tokenEnd || next.kind == tokenRParen || isKeyword(next, "or") || isKeyword(next, "and") {
+			return nil, &SyntaxError{Message: "nothing to negate after " + tok.text, Pos: tok.pos, Token: tok.text}
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Node: node}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses: "(" or ")" | term | word | quoted
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenLParen:
+		if p.peek().kind == tokenRParen {
+			return nil, &SyntaxError{Message: "empty parentheses", Pos: tok.pos, Token: "()"}
+		}
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRParen {
+			return nil, &SyntaxError{Message: "missing closing parenthesis", Pos: tok.pos, Token: tok.text}
+		}
+		p.next()
+		return node, nil
+
+	case tokenWord, tokenQuote:
+		return &Term{Field: "name", Op: OpMatch, Value: tok.value, Pos: tok.pos}, nil
+
+	case tokenTerm:
+		return newTerm(tok)
+
+	default:
+		return nil, p.unexpected(tok)
+	}
+}
+
+// newTerm validates a term token against its field.
+func newTerm(tok token) (*Term, error) {
+	field, ok := LookupField(tok.field)
+	if !ok {
+		return nil, &SyntaxError{Message: "unknown field " + strconv.Quote(tok.field), Pos: tok.pos, Token: tok.text}
+	}
+
+	term := &Term{Field: field.Name, Op: tok.op, Value: tok.value, Pos: tok.pos}
+
+	switch field.Kind {
+	case KindText:
+		if tok.op != OpMatch && tok.op != OpEqual && tok.op != OpNotEqual {
+			return nil, &SyntaxError{
+				Message: "operator " + string(tok.op) + " needs a numeric field, " + field.Name + " is text",
+				Pos:     tok.pos,
+				Token:   tok.text,
+			}
+		}
+
+	case KindNumber:
+		number, err := strconv.Atoi(tok.value)
+		if err != nil {
+			return nil, &SyntaxError{
+				Message: field.Name + " takes a whole number",
+				Pos:     tok.valuePos,
+				Token:   tok.value,
+			}
+		}
+		term.Number = number
+
+	case KindFormat:
+		if tok.op != OpMatch && tok.op != OpEqual {
+			return nil, &SyntaxError{Message: field.Name + " only supports : and =", Pos: tok.pos, Token: tok.text}
+		}
+		info, ok := domain.GetFormatInfo(tok.value)
+		if !ok {
+			return nil, &SyntaxError{Message: "unknown format " + strconv.Quote(tok.value), Pos: tok.valuePos, Token: tok.value}
+		}
+		term.Value = string(info.ID)
+	}
+
+	return term, nil
+}
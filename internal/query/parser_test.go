@@ -0,0 +1,40 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSyntaxError(t *testing.T) {
+	tests := []struct {
+		query   string
+		pos     int
+		message string
+	}{
+		{``, 0, `query is empty`},
+		{`   `, 0, `query is empty`},
+		{`c:ninja or`, 10, `unexpected end of query`},
+		{`c:ninja zz:top`, 8, `unknown field "zz" at position 9: "zz:top"`},
+		{`pow>=four`, 5, `power takes a whole number at position 6: "four"`},
+		{`(c:ninja or t:attack`, 0, `missing closing parenthesis at position 1: "("`},
+		{`c:ninja)`, 7, `unexpected token at position 8: ")"`},
+		{`kw:"go again`, 3, `unterminated quoted string at position 4: "\"go again"`},
+		{`c:ninja not`, 8, `nothing to negate after not at position 9: "not"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse() error = %v, want a syntax error", err)
+			}
+			if syntaxErr.Pos != tt.pos {
+				t.Errorf("Parse() error at %d, want %d", syntaxErr.Pos, tt.pos)
+			}
+			if got := err.Error(); got != tt.message {
+				t.Errorf("Parse() error = %q, want %q", got, tt.message)
+			}
+		})
+	}
+}
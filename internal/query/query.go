@@ -0,0 +1,177 @@
+// Package query parses the card search language, a Scryfall-style syntax
+// such as `c:ninja t:attack pow>=4 kw:"go again" -legal:blitz`, into an
+// abstract syntax tree.
+//
+// Terms are ANDed together unless joined by "or"; "-" or "not" negates a
+// term, and parentheses group. A bare word matches card names.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Node is a node of a parsed query.
+type Node interface {
+	// String returns the node in canonical query syntax.
+	String() string
+}
+
+// And matches cards matching every one of its nodes.
+type And struct {
+	Nodes []Node
+}
+
+// Or matches cards matching any one of its nodes.
+type Or struct {
+	Nodes []Node
+}
+
+// Not matches cards that do not match its node.
+type Not struct {
+	Node Node
+}
+
+// Term compares one card field with a value.
+type Term struct {
+	Field  string // canonical field name, e.g. "class" for "c"
+	Op     Operator
+	Value  string
+	Number int // Value parsed, for numeric fields
+	Pos    int // byte offset of the term in the query
+}
+
+// Operator is the comparison a term applies.
+type Operator string
+
+const (
+	OpMatch        Operator = ":"
+	OpEqual        Operator = "="
+	OpNotEqual     Operator = "!="
+	OpLess         Operator = "<"
+	OpLessEqual    Operator = "<="
+	OpGreater      Operator = ">"
+	OpGreaterEqual Operator = ">="
+)
+
+// Compare reports whether a value compares to the term's number as the
+// operator requires.
+func (op Operator) Compare(value, number int) bool {
+	switch op {
+	case OpMatch, OpEqual:
+		return value == number
+	case OpNotEqual:
+		return value != number
+	case OpLess:
+		return value < number
+	case OpLessEqual:
+		return value <= number
+	case OpGreater:
+		return value > number
+	case OpGreaterEqual:
+		return value >= number
+	default:
+		return false
+	}
+}
+
+func (n *And) String() string { return joinNodes(n.Nodes, " ") }
+
+func (n *Or) String() string { return "(" + joinNodes(n.Nodes, " or ") + ")" }
+
+func (n *Not) String() string { return "-" + n.Node.String() }
+
+func (t *Term) String() string {
+	value := t.Value
+	if value == "" || strings.ContainsAny(value, " \t()\"") {
+		value = strconv.Quote(value)
+	}
+	return t.Field + string(t.Op) + value
+}
+
+func joinNodes(nodes []Node, sep string) string {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		parts[i] = node.String()
+	}
+	return strings.Join(parts, sep)
+}
+
+// Kind is the type of values a field holds.
+type Kind int
+
+const (
+	// KindText fields match with ":" (contains), "=" and "!=" (exact,
+	// ignoring case).
+	KindText Kind = iota
+	// KindNumber fields take integers and every operator.
+	KindNumber
+	// KindFormat fields take a format ID such as "blitz" or "cc".
+	KindFormat
+)
+
+// Field describes a field terms can query.
+type Field struct {
+	Name        string
+	Aliases     []string
+	Kind        Kind
+	Description string
+}
+
+// Fields lists every queryable field.
+var Fields = []Field{
+	{Name: "name", Aliases: []string{"n"}, Kind: KindText, Description: "card name"},
+	{Name: "class", Aliases: []string{"c"}, Kind: KindText, Description: "class, e.g. ninja"},
+	{Name: "type", Aliases: []string{"t"}, Kind: KindText, Description: "type line, or an exact type with ="},
+	{Name: "keyword", Aliases: []string{"kw", "k"}, Kind: KindText, Description: "card keyword, e.g. \"go again\""},
+	{Name: "text", Aliases: []string{"o", "oracle"}, Kind: KindText, Description: "functional text"},
+	{Name: "set", Aliases: []string{"s", "e"}, Kind: KindText, Description: "set code of any printing"},
+	{Name: "rarity", Aliases: []string{"r"}, Kind: KindText, Description: "rarity code or name of any printing"},
+	{Name: "artist", Aliases: []string{"a"}, Kind: KindText, Description: "artist of any printing"},
+	{Name: "color", Aliases: []string{"colour"}, Kind: KindText, Description: "color, e.g. red"},
+	{Name: "pitch", Aliases: []string{"p"}, Kind: KindNumber, Description: "pitch value"},
+	{Name: "cost", Aliases: []string{"cmc"}, Kind: KindNumber, Description: "resource cost"},
+	{Name: "power", Aliases: []string{"pow"}, Kind: KindNumber, Description: "power"},
+	{Name: "defense", Aliases: []string{"def"}, Kind: KindNumber, Description: "defense"},
+	{Name: "health", Aliases: []string{"hp", "life"}, Kind: KindNumber, Description: "hero health"},
+	{Name: "intelligence", Aliases: []string{"int"}, Kind: KindNumber, Description: "hero intelligence"},
+	{Name: "arcane", Kind: KindNumber, Description: "arcane damage"},
+	{Name: "legal", Aliases: []string{"f", "format"}, Kind: KindFormat, Description: "legal in a format"},
+	{Name: "banned", Kind: KindFormat, Description: "banned in a format"},
+	{Name: "suspended", Kind: KindFormat, Description: "suspended in a format"},
+	{Name: "restricted", Kind: KindFormat, Description: "restricted in a format"},
+}
+
+// LookupField returns the field with the given name or alias
+// (case-insensitive).
+func LookupField(name string) (Field, bool) {
+	name = strings.ToLower(name)
+	for _, field := range Fields {
+		if field.Name == name {
+			return field, true
+		}
+		for _, alias := range field.Aliases {
+			if alias == name {
+				return field, true
+			}
+		}
+	}
+	return Field{}, false
+}
+
+// SyntaxError reports a query that cannot be parsed, and where.
+type SyntaxError struct {
+	Message string
+	Pos     int    // byte offset of the offending token in the query
+	Token   string // the offending token, empty at the end of the query
+}
+
+// Error returns the message, with the position and token unless the error is
+// at the end of the query, which the message then says.
+func (e *SyntaxError) Error() string {
+	if e.Token == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s at position %d: %q", e.Message, e.Pos+1, e.Token)
+}
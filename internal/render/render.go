@@ -0,0 +1,172 @@
+// Package render turns card text containing icon tokens such as "{r}" or
+// "{p}" into plain text, HTML or Markdown.
+package render
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/oleiade/goagain/internal/domain"
+)
+
+// Mode selects how card text is rendered.
+type Mode string
+
+const (
+	// ModeRaw leaves the text as published, icon tokens included.
+	ModeRaw Mode = "raw"
+	// ModePlain expands icon tokens to words, e.g. "{r}" to "resource".
+	ModePlain Mode = "plain"
+	// ModeHTML escapes the text, converts its Markdown emphasis and replaces
+	// icon tokens with labelled inline spans.
+	ModeHTML Mode = "html"
+	// ModeMarkdown keeps the text's Markdown emphasis and expands icon tokens
+	// to words.
+	ModeMarkdown Mode = "markdown"
+)
+
+// ParseMode parses a render mode. An empty value selects ModeRaw.
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(value)); mode {
+	case "":
+		return ModeRaw, nil
+	case ModeRaw, ModePlain, ModeHTML, ModeMarkdown:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid render mode %q, expected plain, html, markdown or raw", value)
+	}
+}
+
+// iconWords are the words icon tokens expand to. Tokens missing from the
+// table fall back to their icon.json description.
+var iconWords = map[string]string{
+	"{r}": "resource",
+	"{p}": "power",
+	"{d}": "defense",
+	"{h}": "life",
+	"{I}": "intellect",
+	"{t}": "tap",
+	"{u}": "untap",
+}
+
+var (
+	iconPattern    = regexp.MustCompile(`\{[A-Za-z]\}`)
+	iconRunPattern = regexp.MustCompile(`(\{[A-Za-z]\})(?:\{[A-Za-z]\})*`)
+	boldPattern    = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	italicPattern  = regexp.MustCompile(`\*(.+?)\*`)
+)
+
+// Renderer renders card text using the icons documented in icon.json.
+type Renderer struct {
+	icons map[string]*domain.Icon
+}
+
+// NewRenderer creates a renderer for the given icons.
+func NewRenderer(icons []*domain.Icon) *Renderer {
+	r := &Renderer{icons: make(map[string]*domain.Icon, len(icons))}
+	for _, icon := range icons {
+		r.icons[icon.Icon] = icon
+	}
+	return r
+}
+
+// Render renders the text in the given mode. Plain mode expects text without
+// Markdown, such as Card.FunctionalTextPlain; the other modes expect the
+// Markdown text, such as Card.FunctionalText.
+func (r *Renderer) Render(text string, mode Mode) string {
+	switch mode {
+	case ModePlain, ModeMarkdown:
+		return r.expandWords(text)
+	case ModeHTML:
+		return r.toHTML(text)
+	default:
+		return text
+	}
+}
+
+// word returns the word an icon token expands to, and false for tokens that
+// are neither in icon.json nor in the word table.
+func (r *Renderer) word(token string) (string, bool) {
+	if word, ok := iconWords[token]; ok {
+		return word, true
+	}
+	if icon, ok := r.icons[token]; ok {
+		return strings.ToLower(icon.Description), true
+	}
+	return "", false
+}
+
+// expandWords replaces icon tokens with words. A run of the same token is
+// counted, so "{r}{r}" reads "2 resources", and spaces are added where a
+// token touches a number or word, so "+1{p}" reads "+1 power".
+func (r *Renderer) expandWords(text string) string {
+	var b strings.Builder
+	last := 0
+
+	for _, loc := range iconRunPattern.FindAllStringIndex(text, -1) {
+		run := text[loc[0]:loc[1]]
+		b.WriteString(text[last:loc[0]])
+		last = loc[1]
+
+		tokens := iconPattern.FindAllString(run, -1)
+		var words []string
+		for i := 0; i < len(tokens); {
+			j := i
+			for j < len(tokens) && tokens[j] == tokens[i] {
+				j++
+			}
+
+			word, ok := r.word(tokens[i])
+			switch {
+			case !ok:
+				words = append(words, strings.Repeat(tokens[i], j-i))
+			case j-i > 1:
+				words = append(words, fmt.Sprintf("%d %ss", j-i, word))
+			default:
+				words = append(words, word)
+			}
+			i = j
+		}
+
+		if loc[0] > 0 && isWordChar(text[loc[0]-1]) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(strings.Join(words, " "))
+		if loc[1] < len(text) && isWordChar(text[loc[1]]) {
+			b.WriteByte(' ')
+		}
+	}
+
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// toHTML escapes the text, converts its Markdown emphasis and line breaks and
+// replaces icon tokens with spans labelled for screen readers.
+func (r *Renderer) toHTML(text string) string {
+	escaped := html.EscapeString(text)
+	escaped = boldPattern.ReplaceAllString(escaped, "<strong>$1</strong>")
+	escaped = italicPattern.ReplaceAllString(escaped, "<em>$1</em>")
+
+	escaped = iconPattern.ReplaceAllStringFunc(escaped, func(token string) string {
+		word, ok := r.word(token)
+		if !ok {
+			return token
+		}
+
+		title := word
+		if icon, ok := r.icons[token]; ok {
+			title = icon.Description
+		}
+		return fmt.Sprintf(`<span class="icon icon-%s" role="img" aria-label="%s" title="%s">%s</span>`,
+			token[1:2], html.EscapeString(word), html.EscapeString(title), token)
+	})
+
+	return strings.ReplaceAll(escaped, "\n", "<br>")
+}
+
+func isWordChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/oleiade/goagain/internal/data"
+	"github.com/oleiade/goagain/internal/observability"
+)
+
+// ReloadOnSignal reloads the provider's data whenever the process receives
+// SIGHUP, until ctx is done.
+func ReloadOnSignal(ctx context.Context, provider *data.Provider, logger *slog.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				logger.Info("Reloading data on SIGHUP", slog.String("source", provider.Source()))
+				_, _ = reload(provider, logger)
+			}
+		}
+	}()
+}
+
+// ReloadHandler returns a handler that reloads the provider's data. Requests
+// must carry the token as a bearer token in the Authorization header.
+func ReloadHandler(provider *data.Provider, token string, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
+			return
+		}
+
+		logger.Info("Reloading data on admin request", slog.String("source", provider.Source()))
+		store, err := reload(provider, logger)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "reload failed: " + err.Error()})
+			return
+		}
+
+		stats, _ := store.Stats()
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status":       "reloaded",
+			"data_version": store.Version,
+			"loaded_at":    store.LoadedAt.Format(time.RFC3339),
+			"stats":        stats,
+		})
+	}
+}
+
+func reload(provider *data.Provider, logger *slog.Logger) (*data.Store, error) {
+	start := time.Now()
+
+	store, err := provider.Reload()
+	if err != nil {
+		logger.Error("Failed to reload data, keeping current data", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	stats, _ := store.Stats()
+	observability.LogDataLoaded(logger, stats)
+	if issues := store.Validate(); len(issues) > 0 {
+		logger.Warn("Reloaded data has validation issues, run goagain validate for details",
+			slog.Int("issues", len(issues)),
+			slog.String("first", issues[0].String()),
+		)
+	}
+	logger.Info("Data reloaded",
+		slog.String("data_version", store.Version),
+		slog.Duration("duration", time.Since(start)),
+	)
+
+	return store, nil
+}
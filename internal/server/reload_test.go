@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oleiade/goagain/internal/data"
+)
+
+// writeDataDir copies the embedded data files to a temporary directory, with
+// the given card.json, which is not embedded in the repository.
+func writeDataDir(t *testing.T, cardJSON string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.CopyFS(dir, os.DirFS("../data/english")); err != nil {
+		t.Fatalf("copying data files: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "card.json"), []byte(cardJSON), 0o644); err != nil {
+		t.Fatalf("writing card.json: %v", err)
+	}
+
+	return dir
+}
+
+func TestReloadHandler(t *testing.T) {
+	dir := writeDataDir(t, `[{"unique_id": "a", "name": "First"}]`)
+	provider, err := data.NewProvider(data.ProviderConfig{Dir: dir}, nil)
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	handler := ReloadHandler(provider, "secret", slog.New(slog.DiscardHandler))
+
+	post := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/reload", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	for _, authorization := range []string{"", "secret", "Bearer wrong", "Basic secret"} {
+		if rec := post(authorization); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Reload with Authorization %q = %d, want %d", authorization, rec.Code, http.StatusUnauthorized)
+		}
+	}
+
+	before := provider.Store()
+	cards := `[{"unique_id": "a", "name": "First"}, {"unique_id": "b", "name": "Second"}]`
+	if err := os.WriteFile(filepath.Join(dir, "card.json"), []byte(cards), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := post("Bearer secret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Reload = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var body struct {
+		Status      string `json:"status"`
+		DataVersion string `json:"data_version"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if body.Status != "reloaded" || body.DataVersion != provider.Store().Version || body.DataVersion == before.Version {
+		t.Errorf("Reload response = %+v, want the new data version %s", body, provider.Store().Version)
+	}
+
+	// A failed reload reports an error and keeps the current data
+	if err := os.WriteFile(filepath.Join(dir, "card.json"), []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	current := provider.Store()
+	if rec := post("Bearer secret"); rec.Code != http.StatusInternalServerError {
+		t.Errorf("Failed reload = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if provider.Store() != current {
+		t.Error("A failed reload should keep the current store")
+	}
+}